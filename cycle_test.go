@@ -0,0 +1,171 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/fzerorubigd/bgg-hotness/internal/aggregate"
+	"github.com/fzerorubigd/bgg-hotness/internal/bggtest"
+	"github.com/fzerorubigd/bgg-hotness/internal/cleanup"
+	"github.com/fzerorubigd/bgg-hotness/internal/config"
+	"github.com/fzerorubigd/bgg-hotness/internal/hotness"
+	"github.com/fzerorubigd/bgg-hotness/internal/sheetexec"
+	"github.com/fzerorubigd/bgg-hotness/internal/sheetstest"
+	"github.com/fzerorubigd/bgg-hotness/internal/snapshot"
+)
+
+// applyList runs the command list a command wrote to path against the spreadsheet, the
+// way the workflows pipe it into exec.
+func applyList(t *testing.T, cfg config.Config, path string) {
+	t.Helper()
+	in, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer in.Close()
+	out, err := os.Create(filepath.Join(t.TempDir(), "results.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer out.Close()
+	stdin, stdout := os.Stdin, os.Stdout
+	os.Stdin, os.Stdout = in, out
+	defer func() { os.Stdin, os.Stdout = stdin, stdout }()
+	if err := sheetexec.Run(context.Background(), cfg, nil); err != nil {
+		t.Fatalf("exec %s: %v", filepath.Base(path), err)
+	}
+}
+
+// The daily, aggregate and cleanup cycle, offline: fetch records today's hot list from
+// the BGG fixtures, aggregate ranks the window from the CSV export and names the games
+// from BGG, and cleanup archives and removes what the retention policy expired. Every
+// command list goes through exec against the Sheets stand-in, as it does in CI.
+func TestDailyAggregateCleanupCycle(t *testing.T) {
+	for _, env := range []string{"GSHEET_CREDENTIALS_FILE", "GSHEET_CLIENT_EMAIL", "GSHEET_PRIVATE_KEY", "FEED_FILE", "GITHUB_OUTPUT"} {
+		t.Setenv(env, "")
+	}
+	t.Setenv("BGG_TOKEN", "token")
+	sheets := sheetstest.NewServer()
+	defer sheets.Close()
+	bgg := bggtest.NewServer()
+	defer bgg.Close()
+
+	now := time.Now()
+	day := func(daysAgo int) string { return now.AddDate(0, 0, -daysAgo).Format(time.DateOnly) }
+	header := []string{"Date"}
+	for i := 1; i <= 50; i++ {
+		header = append(header, fmt.Sprint(i))
+	}
+	gid := sheets.AddSheet("doc", "Aggregate", [][]string{
+		header,
+		{day(2), "342942", "266192", "224517", "174430", "167791"},
+		{day(1), "342942", "224517", "266192", "167791", "174430"},
+	})
+	old := day(400)
+	daily := [][]string{{"Rank", "BGGID", "Change", "Link", "Name"}, {"1", "342942", "0", "https://boardgamegeek.com/boardgame/342942/", "Ark Nova"}}
+	sheets.AddSheet("doc", old, daily)
+	sheets.AddSheet("doc", old+"_14-days", daily)
+	sheets.AddSheet("doc", "Monthly - 2024-1", daily)
+
+	cfg := config.Config{
+		DocumentID:     "doc",
+		PageID:         int(gid),
+		BGGEndpoint:    bgg.URL,
+		SheetsEndpoint: sheets.URL + "/",
+		ExportEndpoint: sheets.URL,
+	}
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	fetched := filepath.Join(dir, "fetch.txt")
+	if err := hotness.Run(ctx, cfg, []string{"-output=actions:" + fetched}); err != nil {
+		t.Fatalf("fetch: %v", err)
+	}
+	applyList(t, cfg, fetched)
+	today := day(0)
+	if got := sheets.Rows("doc", today); len(got) != 6 || got[1][4] != "Ark Nova" {
+		t.Fatalf("today's worksheet = %v, want the five fixture games, Ark Nova first", got)
+	}
+	if rows := sheets.Rows("doc", "Aggregate"); !reflect.DeepEqual(rows[len(rows)-1][:2], []string{today, "342942"}) {
+		t.Fatalf("last Aggregate ballot = %v, want today's list", rows[len(rows)-1])
+	}
+
+	aggregated := filepath.Join(dir, "aggregate.txt")
+	if err := aggregate.Run(ctx, cfg, []string{"-output=actions:" + aggregated}); err != nil {
+		t.Fatalf("aggregate: %v", err)
+	}
+	applyList(t, cfg, aggregated)
+	ranking := sheets.Rows("doc", today+"_14-days")
+	if len(ranking) != 6 {
+		t.Fatalf("ranking worksheet = %v, want a header and the five games", ranking)
+	}
+	if want := []string{"1", "342942", "4", "https://boardgamegeek.com/boardgame/342942/", "Ark Nova"}; !reflect.DeepEqual(ranking[1], want) {
+		t.Errorf("ranking first row = %v, want %v", ranking[1], want)
+	}
+
+	archive := filepath.Join(dir, "archive")
+	removed := filepath.Join(dir, "cleanup.txt")
+	if err := cleanup.Run(ctx, cfg, []string{"-archive-dir=" + archive, "-output=actions:" + removed}); err != nil {
+		t.Fatalf("cleanup: %v", err)
+	}
+	applyList(t, cfg, removed)
+	titles := sheets.Titles("doc")
+	for _, want := range []string{"Aggregate", today, today + "_14-days", "Monthly - 2024-1"} {
+		if !slices.Contains(titles, want) {
+			t.Errorf("worksheets %v lack %q", titles, want)
+		}
+	}
+	for _, gone := range []string{old, old + "_14-days"} {
+		if slices.Contains(titles, gone) {
+			t.Errorf("expired worksheet %q was not removed", gone)
+		}
+		if got, err := (snapshot.Store{Dir: archive}).Read(gone); err != nil || !reflect.DeepEqual(got, daily) {
+			t.Errorf("archive of %q = %v, %v; want its rows", gone, got, err)
+		}
+	}
+}
+
+// A feed that cannot be written fails the aggregate run, but only after the ranking is
+// written, so the sheet update still has its commands.
+func TestAggregateFeedFailureFailsRun(t *testing.T) {
+	for _, env := range []string{"GSHEET_CREDENTIALS_FILE", "GSHEET_CLIENT_EMAIL", "GSHEET_PRIVATE_KEY", "GITHUB_OUTPUT"} {
+		t.Setenv(env, "")
+	}
+	t.Setenv("BGG_TOKEN", "token")
+	sheets := sheetstest.NewServer()
+	defer sheets.Close()
+	bgg := bggtest.NewServer()
+	defer bgg.Close()
+
+	header := []string{"Date"}
+	for i := 1; i <= 50; i++ {
+		header = append(header, fmt.Sprint(i))
+	}
+	gid := sheets.AddSheet("doc", "Aggregate", [][]string{
+		header,
+		{time.Now().AddDate(0, 0, -1).Format(time.DateOnly), "342942", "266192"},
+	})
+	dir := t.TempDir()
+	// The feed file's directory does not exist, so the feed cannot be written.
+	t.Setenv("FEED_FILE", filepath.Join(dir, "missing", "feed.xml"))
+	cfg := config.Config{
+		DocumentID:     "doc",
+		PageID:         int(gid),
+		BGGEndpoint:    bgg.URL,
+		ExportEndpoint: sheets.URL,
+	}
+	out := filepath.Join(dir, "aggregate.json")
+	if err := aggregate.Run(context.Background(), cfg, []string{"-output=json:" + out}); err == nil {
+		t.Fatal("aggregate with an unwritable feed returned nil")
+	}
+	if b, err := os.ReadFile(out); err != nil || !strings.Contains(string(b), "342942") {
+		t.Errorf("ranking output = %q, %v; want it written before the feed failed", b, err)
+	}
+}
@@ -0,0 +1,463 @@
+// Package aggregate is the aggregate subcommand: it ranks the daily hot lists of a
+// window into one list and publishes it to the sheet and the Atom feeds.
+package aggregate
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/fzerorubigd/bggo"
+	"resenje.org/schulze"
+
+	"github.com/fzerorubigd/bgg-hotness/internal/bgg"
+	"github.com/fzerorubigd/bgg-hotness/internal/cli"
+	"github.com/fzerorubigd/bgg-hotness/internal/config"
+	"github.com/fzerorubigd/bgg-hotness/internal/gsheet"
+	"github.com/fzerorubigd/bgg-hotness/internal/history"
+	"github.com/fzerorubigd/bgg-hotness/internal/output"
+	"github.com/fzerorubigd/bgg-hotness/internal/snapshot"
+)
+
+const (
+	// defaultExportEndpoint serves the CSV export of a worksheet; -export-endpoint
+	// replaces it, e.g. with a local Sheets stand-in.
+	defaultExportEndpoint = "https://spreadsheets.google.com"
+	exportPath            = "/feeds/download/spreadsheets/Export?key=%s&exportFormat=csv&gid=%d"
+	batchSize             = 20
+)
+
+func getCSV(ctx context.Context, endpoint, doc string, page int, dateIn, dateOut time.Time) ([][]string, error) {
+	u := strings.TrimSuffix(endpoint, "/") + fmt.Sprintf(exportPath, url.QueryEscape(doc), page)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	defer resp.Body.Close()
+	// Without this an error page is parsed as CSV and reported as a header mismatch,
+	// which hides the actual failure (an unshared document, a wrong gid).
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("export of document %q page %d: %s", doc, page, resp.Status)
+	}
+	csReader := csv.NewReader(resp.Body)
+	headers, err := csReader.Read()
+	if err != nil {
+		return nil, err
+	}
+	expected := make([]string, 51)
+
+	expected[0] = "Date"
+	for i := 1; i < len(expected); i++ {
+		expected[i] = fmt.Sprint(i)
+	}
+
+	if len(headers) != len(expected) {
+		return nil, fmt.Errorf("the header need to have exactly %d items but has %d", len(expected), len(headers))
+	}
+
+	for i := range expected {
+		if expected[i] != headers[i] {
+			return nil, fmt.Errorf("headers do not match %s => %s", expected[i], headers[i])
+		}
+	}
+
+	var res [][]string
+	for {
+		ln, err := csReader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		date, err := time.Parse(time.DateOnly, ln[0])
+		if err != nil {
+			// Err?
+			continue
+		}
+		// The export pads a list shorter than the header with empty cells, which are
+		// not games.
+		for len(ln) > 1 && ln[len(ln)-1] == "" {
+			ln = ln[:len(ln)-1]
+		}
+		if date.After(dateIn) && date.Before(dateOut) {
+			res = append(res, ln)
+		}
+	}
+
+	return res, nil
+}
+
+// or returns v, or def when v is empty.
+func or(v, def string) string {
+	if v != "" {
+		return v
+	}
+	return def
+}
+
+func options(in [][]string) []string {
+	m := make(map[string]struct{})
+	for i := range in {
+		for _, v := range in[i][1:] {
+			m[v] = struct{}{}
+		}
+	}
+
+	ret := make([]string, 0, len(m))
+	for i := range m {
+		ret = append(ret, i)
+	}
+
+	return ret
+}
+
+func toMap(in []string) schulze.Ballot[string] {
+	res := schulze.Ballot[string]{}
+	for i, v := range in[1:] {
+		res[v] = i + 1
+	}
+
+	return res
+}
+
+// rankingCommands creates the worksheet for this ranking and writes the ranked rows,
+// header first, into it.
+func rankingCommands(title string, data [][]string) gsheet.List {
+	return gsheet.List{
+		gsheet.AddWorksheet{WorksheetTitle: title},
+		gsheet.UpdateData{
+			Data:           data,
+			MinCol:         1,
+			Range:          fmt.Sprintf("%s!A1:E%d", title, len(data)),
+			WorksheetTitle: title,
+		},
+	}
+}
+
+// thingGetter is the part of the bggo client rankedRows uses, so tests can stand in for
+// it.
+type thingGetter interface {
+	GetThings(ctx context.Context, req bggo.GetThingsRequest) ([]bggo.ThingResult, error)
+}
+
+// lookupThings fetches ids from BGG in batches of batchSize and indexes the results by
+// id. An id BGG does not return is absent from the map.
+func lookupThings(ctx context.Context, c thingGetter, ids []int64) (map[int64]bggo.ThingResult, error) {
+	byID := make(map[int64]bggo.ThingResult, len(ids))
+	for idx := 0; idx < len(ids); idx += batchSize {
+		nextBatch := ids[idx:min(idx+batchSize, len(ids))]
+		things, err := c.GetThings(ctx, bggo.GetThingsRequest{IDs: nextBatch})
+		if err != nil {
+			return nil, err
+		}
+
+		// BGG returns things in its own order and silently drops invalid/retired
+		// IDs, so index the results by ID and look up each requested id rather than
+		// assuming the response aligns positionally with the request.
+		for _, t := range things {
+			byID[t.ID] = t
+		}
+	}
+	return byID, nil
+}
+
+// rankedRows builds the [rank, id, wins, link, name] row for each ranked id, looking the
+// names up on BGG in batches of batchSize, and returns the things it looked up for the
+// feed's thumbnails. result is the Schulze order ids was read from, so result[i] is the
+// rank-(i+1) choice.
+func rankedRows(ctx context.Context, c thingGetter, ids []int64, result []schulze.Result[string]) ([][]string, map[int64]bggo.ThingResult, error) {
+	byID, err := lookupThings(ctx, c, ids)
+	if err != nil {
+		return nil, nil, err
+	}
+	// Size to the number of ranked ids actually produced, not the requested count:
+	// the Schulze result can yield fewer distinct choices than count, and a
+	// count-sized slice leaves the tail nil, which is marshalled to the sheet (and
+	// would be rendered into the feed) as empty rows. Pre-existing; fixed here in
+	// passing because the feed is what would make those empty rows user-visible.
+	data := make([][]string, len(ids))
+	for i, id := range ids {
+		// On a miss (id dropped upstream), emit the row with the known id and a
+		// blank name rather than panicking. Rank (i+1) and Wins (result[i]) come
+		// from the Schulze order and are correct (PR #170).
+		name := ""
+		if t, ok := byID[id]; ok {
+			name = t.Name
+		}
+		data[i] = append(data[i],
+			fmt.Sprint(i+1),
+			fmt.Sprint(id),
+			fmt.Sprint(result[i].Wins),
+			fmt.Sprintf("https://boardgamegeek.com/boardgame/%d/", id),
+			name)
+	}
+	return data, byID, nil
+}
+
+// aggregationPeriod computes the date window [dayIn, dayOut] and the worksheet/feed
+// title for a run. now is injected rather than read internally so both the rolling
+// and the -year/-month paths are testable — and so the invariant the feed's
+// published field rests on is checkable: published is the END OF THE PERIOD THIS
+// ENTRY DESCRIBES. On the rolling path a window ends now, so dayOut is wall-clock
+// and that is correct as written; on -year/-month it is a fixed period-end instant.
+// Validation of year/month stays in the caller so this function is pure.
+//
+// The title is deliberately built from the pre-clamp days, matching prior
+// behaviour; the clamp to [7, 500] applies only to the window.
+func aggregationPeriod(now time.Time, days, year, month int) (dayIn, dayOut time.Time, title string) {
+	title = fmt.Sprintf("%s_%d-days", now.Format(time.DateOnly), days)
+	if days < 7 {
+		days = 7
+	}
+	if days > 500 {
+		days = 500
+	}
+	window := time.Hour * 24 * time.Duration(days)
+	dayIn, dayOut = now.Add(-window), now
+	if year != 0 {
+		if month != 0 {
+			dayIn = time.Date(year, time.Month(month), 1, 0, 0, 0, 0, time.Local)
+			dayOut = time.Date(year, time.Month(month)+1, 1, 0, 0, 0, 0, time.Local).Add(-time.Second)
+			title = fmt.Sprintf("Monthly - %d-%d", year, month)
+		} else {
+			dayIn = time.Date(year, 1, 1, 0, 0, 0, 0, time.Local)
+			dayOut = time.Date(year+1, 1, 1, 0, 0, 0, 0, time.Local).Add(-time.Second)
+			title = fmt.Sprintf("Yearly - %d", year)
+		}
+	}
+	return dayIn, dayOut, title
+}
+
+// Run ranks the ballots on the Aggregate worksheet for a window with the Schulze
+// method and writes the commands that record the ranking on its own worksheet. With a
+// feed configured it also publishes the ranking to that feed.
+func Run(ctx context.Context, cfg config.Config, args []string) error {
+	fs := flag.NewFlagSet("aggregate", flag.ContinueOnError)
+	var (
+		documentID  string
+		pageID      int
+		exportURL   string
+		bggEndpoint string
+		days        int
+		year        int
+		month       int
+		count       int
+		perGame     bool
+		rss         bool
+		withHistory bool
+		entryCap    int
+		maxAgeDays  int
+		archiveDir  string
+		baseURL     string
+		siteURL     string
+		hub         string
+		changedFile string
+		feedName    string
+		out         string
+	)
+	fs.StringVar(&documentID, "document-id", cfg.DocumentID, "The document id to get the data from")
+	fs.IntVar(&pageID, "page-id", cfg.PageID, "The page id in document")
+	fs.StringVar(&exportURL, "export-endpoint", or(cfg.ExportEndpoint, defaultExportEndpoint), "Base URL of the spreadsheet CSV export, e.g. a local Sheets stand-in")
+	fs.StringVar(&bggEndpoint, "bgg-endpoint", cfg.BGGEndpoint, "Base URL to send BGG API requests to instead of boardgamegeek.com, e.g. a fixture server")
+	fs.IntVar(&days, "days", 14, "Number of days to get the report, will be ignored if year is set")
+	fs.IntVar(&year, "year", 0, "Year to get the report, if set, the days will be ignored")
+	fs.IntVar(&month, "month", 0, "Month to get the report, if set, year should be sert too")
+	fs.IntVar(&count, "count", 50, "Number of items to get the report")
+	fs.BoolVar(&perGame, "per-game", false, "Emit one feed entry per game (updated in place, each linking its BGG page) instead of a single digest entry for the run")
+	fs.StringVar(&feedName, "feed", "", "Name of the feed in the config file to publish to (weekly, monthly, yearly); FEED_FILE and FEED_TITLE override its file and title")
+	fs.StringVar(&baseURL, "feed-base-url", cfg.FeedBaseURL, "Public URL the feed files are served under, for the feeds' rel=\"self\" link")
+	fs.StringVar(&siteURL, "site-url", cfg.SiteURL, "Page the feeds are about, for their rel=\"alternate\" link")
+	fs.StringVar(&hub, "hub", cfg.Hub, "WebSub hub the feeds name as rel=\"hub\"")
+	fs.StringVar(&changedFile, "changed", "", "Append the public URL of each feed file this run changed to this file, one per line, for websub to announce once they are pushed (needs -feed-base-url)")
+	fs.BoolVar(&rss, "rss", false, "Also write the feed as RSS 2.0 next to the Atom file (default the feed's rss setting, or FEED_RSS)")
+	fs.BoolVar(&withHistory, "history", false, "Add each game's rank history to its per-game feed entry (default the feed's history setting, or FEED_HISTORY)")
+	fs.IntVar(&entryCap, "cap", 0, fmt.Sprintf("Most entries the feed keeps, 0 for no limit (default the feed's cap setting, FEED_CAP, or %d)", feedCap))
+	fs.IntVar(&maxAgeDays, "max-age-days", 0, "Move feed entries older than this many days to the archive, 0 for no age limit (default the feed's max_age_days setting, or FEED_MAX_AGE_DAYS)")
+	fs.StringVar(&archiveDir, "archive-dir", cfg.ArchiveDir, "Directory of daily lists, as fetch -archive-dir writes it, for the -history sparkline of recent daily ranks")
+	fs.StringVar(&out, "output", output.Default(), "Where to write the ranking, KIND or KIND:FILE with KIND one of "+output.Kinds)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	feed := cfg.Feed(feedName)
+	fs.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "rss":
+			feed.RSS = rss
+		case "history":
+			feed.History = withHistory
+		case "cap":
+			feed.Cap = &entryCap
+		case "max-age-days":
+			feed.MaxAgeDays = maxAgeDays
+		}
+	})
+	if (feed.Cap != nil && *feed.Cap < 0) || feed.MaxAgeDays < 0 {
+		return errors.New("the feed's cap and max age cannot be negative")
+	}
+	sink, err := output.New(out, os.Stdout)
+	if err != nil {
+		return err
+	}
+
+	if year != 0 {
+		if year < 2023 || year > time.Now().Year() {
+			return errors.New("there is no data before mid 2023")
+		}
+		if month != 0 && (month < 1 || month > 12) {
+			return errors.New("month should be between 1 and 12")
+		}
+	}
+	dayIn, dayOut, today := aggregationPeriod(time.Now(), days, year, month)
+
+	ballots, err := getCSV(ctx, exportURL, documentID, pageID, dayIn, dayOut)
+	if err != nil {
+		return err
+	}
+
+	result, err := schulzeRank(ballots)
+	if err != nil {
+		return err
+	}
+	ids := make([]int64, 0, count)
+	for i := range result {
+		if i >= count {
+			break
+		}
+		id, err := strconv.ParseInt(result[i].Choice, 10, 0)
+		if err != nil {
+			return err
+		}
+		ids = append(ids, id)
+	}
+	token, err := cli.BGGToken()
+	if err != nil {
+		return err
+	}
+	c, err := bgg.NewClient(token, bggEndpoint)
+	if err != nil {
+		return err
+	}
+	data, things, err := rankedRows(ctx, c, ids, result)
+	if err != nil {
+		return err
+	}
+
+	base := []string{
+		"Rank",
+		"BGGID",
+		"Wins",
+		"Link",
+		"Name",
+	}
+	data = append([][]string{base}, data...)
+
+	if err := sink.Write(output.Result{Title: today, Rows: data, Commands: rankingCommands(today, data)}); err != nil {
+		return err
+	}
+
+	// Publish an Atom feed entry for this run, but only after the result above is
+	// written — under Actions stdout is the output protocol the sheet update
+	// consumes, so the additive feed must not be able to regress it. A feed failure
+	// is returned only once that output is complete, so the run still fails.
+	// data[1:] is the ranked rows; data[0] is the header prepended above.
+	if feed.File != "" {
+		// Which shape a run emits is POLICY and is not derivable from any existing
+		// state: the monthly job is `-days=30` with no -year, so it is the same code
+		// path as the weekly job end to end, differing only in the window number, and
+		// the "Monthly - " title is unreachable from any workflow. So the per-game
+		// shape is opt-in via an explicit flag, set only in aggregate.yaml (the weekly
+		// job). Absence means the digest shape, so a future workflow that forgets the
+		// flag produces a harmless digest entry rather than per-game entries that would
+		// overwrite the weekly entries for those games in place.
+		//
+		// The three jobs write three SEPARATE feed files (each sets its own FEED_FILE, or
+		// names its feed in the config file); the feed-level id is derived from that
+		// filename and the feed-level title comes from FEED_TITLE, defaulting to the
+		// weekly feed's title when unset. A wrong title is cosmetic; a wrong id is not,
+		// which is why the title and the feed-level links are configurable and the id is
+		// not, apart from the file.
+		opts := feedOptions{Title: feed.Title, RSS: feed.RSS, BaseURL: baseURL, SiteURL: siteURL, Hub: hub, History: feed.History, Games: gamesFromThings(things)}
+		if opts.Title == "" {
+			opts.Title = defaultFeedTitle
+		}
+		// A configured cap of 0 is "no limit", which feedOptions spells noCap so that its
+		// zero value stays the default cap.
+		if feed.Cap != nil {
+			opts.Cap = *feed.Cap
+			if opts.Cap == 0 {
+				opts.Cap = noCap
+			}
+		}
+		opts.MaxAge = time.Duration(feed.MaxAgeDays) * 24 * time.Hour
+		if opts.History && archiveDir != "" {
+			// Without the daily lists the sparkline falls back to the weekly ranks, so a
+			// store that cannot be read costs the detail, not the feed.
+			recent, err := recentDays(history.History{Store: snapshot.Store{Dir: archiveDir}}, sparkDays)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "feed: daily lists for the history: %v\n", err)
+			}
+			opts.Recent = recent
+		}
+		var (
+			changed []string
+			ferr    error
+		)
+		if perGame {
+			changed, ferr = updateFeedPerGame(feed.File, opts, time.Now(), data[1:])
+		} else {
+			changed, ferr = updateFeedDigest(feed.File, opts, today, dayOut, time.Now(), data[1:])
+		}
+		// Only the files the run changed are listed. The hub is told after the push
+		// (the websub command), not from here: it fetches the topic when told, and before
+		// the push that is still the old content.
+		if ferr == nil && len(changed) > 0 && changedFile != "" {
+			ferr = appendTopics(changedFile, feedTopics(changed, opts))
+		}
+		if ferr != nil {
+			return fmt.Errorf("feed: %w (sheet output unaffected)", ferr)
+		}
+	}
+	return nil
+}
+
+// recentDays is the last n daily lists in h, oldest first.
+func recentDays(h history.History, n int) ([]history.Day, error) {
+	dates, err := h.Dates()
+	if err != nil || len(dates) == 0 {
+		return nil, err
+	}
+	return h.Range(dates[max(0, len(dates)-n)], time.Time{})
+}
+
+// appendTopics appends topics to the file at path, one per line. Without topics (no base
+// URL configured) it says so on stderr and leaves the file alone.
+func appendTopics(path string, topics []string) error {
+	if len(topics) == 0 {
+		fmt.Fprintln(os.Stderr, "feed: changed, but no feed base URL to announce it under")
+		return nil
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintln(f, strings.Join(topics, "\n")); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,21 @@
+package aggregate
+
+import (
+	"testing"
+
+	"github.com/fzerorubigd/bgg-hotness/internal/gsheet"
+)
+
+// The ranking titles contain spaces and an underscore; the unquoted range the list
+// carries must still resolve to the worksheet it adds.
+func TestRankingCommandsValid(t *testing.T) {
+	data := [][]string{
+		{"Rank", "BGGID", "Wins", "Link", "Name"},
+		{"1", "174430", "49", "https://boardgamegeek.com/boardgame/174430/", "Gloomhaven"},
+	}
+	for _, title := range []string{"2026-08-01_7-days", "Monthly - 2026-7", "Yearly - 2025"} {
+		if err := gsheet.Validate(rankingCommands(title, data), []string{"Aggregate"}); err != nil {
+			t.Errorf("%q: %v", title, err)
+		}
+	}
+}
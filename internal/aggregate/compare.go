@@ -0,0 +1,55 @@
+package aggregate
+
+import "time"
+
+// Move is a game in a period's ranking next to its place in the period before.
+type Move struct {
+	Placed
+	// PreviousRank is its rank in the previous period, 0 when it was not ranked.
+	PreviousRank int
+	// Change is the places it climbed, negative for a fall, 0 when it is new.
+	Change int
+	// New is set when it was not in the previous period's top count, so a game that
+	// climbs into the top 10 from 40th is a new entry on a top-10 list.
+	New bool
+}
+
+// Comparison is a period's top count set against the previous period's.
+type Comparison struct {
+	Games   []Move
+	Dropped []Placed // In the previous top count but not this one, at their previous rank.
+}
+
+// Compare compares the full rankings cur and prev, as RankDays returns them, over
+// their top count games. A negative count compares none.
+func Compare(cur, prev []Placed, count int) Comparison {
+	count = max(count, 0)
+	prevRank := make(map[int64]int, len(prev))
+	for _, p := range prev {
+		prevRank[p.ID] = p.Rank
+	}
+	c := Comparison{Games: []Move{}, Dropped: []Placed{}}
+	inTop := map[int64]bool{}
+	for _, p := range cur[:min(count, len(cur))] {
+		inTop[p.ID] = true
+		m := Move{Placed: p, PreviousRank: prevRank[p.ID]}
+		m.New = m.PreviousRank == 0 || m.PreviousRank > count
+		if m.PreviousRank != 0 {
+			m.Change = m.PreviousRank - m.Rank
+		}
+		c.Games = append(c.Games, m)
+	}
+	for _, p := range prev[:min(count, len(prev))] {
+		if !inTop[p.ID] {
+			c.Dropped = append(c.Dropped, p)
+		}
+	}
+	return c
+}
+
+// PreviousPeriod is the period to compare [from, to] with by default: as many days,
+// ending the day before from. to is taken as the start of its day.
+func PreviousPeriod(from, to time.Time) (time.Time, time.Time) {
+	prevTo := from.AddDate(0, 0, -1)
+	return prevTo.AddDate(0, 0, -int(to.Sub(from).Hours()/24)), prevTo
+}
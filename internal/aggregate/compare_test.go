@@ -0,0 +1,40 @@
+package aggregate
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestCompare(t *testing.T) {
+	prev := []Placed{{Rank: 1, ID: 1}, {Rank: 2, ID: 4}, {Rank: 3, ID: 2}, {Rank: 4, ID: 5}}
+	cur := []Placed{{Rank: 1, ID: 3}, {Rank: 2, ID: 2}, {Rank: 3, ID: 5}, {Rank: 4, ID: 1}}
+	got := Compare(cur, prev, 3)
+	want := Comparison{
+		Games: []Move{
+			{Placed: Placed{Rank: 1, ID: 3}, New: true},
+			{Placed: Placed{Rank: 2, ID: 2}, PreviousRank: 3, Change: 1},
+			// Ranked before, but below the top 3: new to this list.
+			{Placed: Placed{Rank: 3, ID: 5}, PreviousRank: 4, Change: 1, New: true},
+		},
+		Dropped: []Placed{{Rank: 1, ID: 1}, {Rank: 2, ID: 4}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Compare =\n%+v\nwant\n%+v", got, want)
+	}
+}
+
+func TestCompareNegativeCount(t *testing.T) {
+	cur := []Placed{{Rank: 1, ID: 1}}
+	if got := Compare(cur, cur, -1); len(got.Games) != 0 || len(got.Dropped) != 0 {
+		t.Errorf("Compare with count -1 = %+v, want nothing compared", got)
+	}
+}
+
+func TestPreviousPeriod(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2026, 8, d, 0, 0, 0, 0, time.UTC) }
+	from, to := PreviousPeriod(day(15), day(28))
+	if !from.Equal(day(1)) || !to.Equal(day(14)) {
+		t.Errorf("PreviousPeriod(15th, 28th) = %v, %v; want the 1st and the 14th", from, to)
+	}
+}
@@ -0,0 +1,60 @@
+package aggregate
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/fzerorubigd/bgg-hotness/internal/sheetstest"
+)
+
+func aggregateSheet() [][]string {
+	header := make([]string, 51)
+	header[0] = "Date"
+	for i := 1; i < len(header); i++ {
+		header[i] = fmt.Sprint(i)
+	}
+	rows := [][]string{header}
+	for _, day := range []string{"2026-07-31", "2026-08-02", "2026-08-05", "not-a-date"} {
+		row := make([]string, 51)
+		row[0] = day
+		for i := 1; i < len(row); i++ {
+			row[i] = fmt.Sprint(1000 + i)
+		}
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+// getCSV against the Sheets stand-in's export endpoint: only ballots strictly inside the
+// window come back, and a row whose date does not parse is skipped.
+func TestGetCSVFromExportEndpoint(t *testing.T) {
+	s := sheetstest.NewServer()
+	defer s.Close()
+	gid := s.AddSheet("doc", "Aggregate", aggregateSheet())
+
+	in := time.Date(2026, 8, 1, 0, 0, 0, 0, time.UTC)
+	out := time.Date(2026, 8, 10, 0, 0, 0, 0, time.UTC)
+	got, err := getCSV(context.Background(), s.URL, "doc", int(gid), in, out)
+	if err != nil {
+		t.Fatalf("getCSV: %v", err)
+	}
+	if len(got) != 2 || got[0][0] != "2026-08-02" || got[1][0] != "2026-08-05" {
+		t.Errorf("ballots = %d rows starting %v, want 2026-08-02 and 2026-08-05", len(got), got)
+	}
+}
+
+// A failed export is reported as such rather than as a header mismatch.
+func TestGetCSVReportsHTTPStatus(t *testing.T) {
+	s := sheetstest.NewServer()
+	defer s.Close()
+	_, err := getCSV(context.Background(), s.URL, "missing", 0, time.Time{}, time.Now())
+	if err == nil {
+		t.Fatal("expected an error for a missing document")
+	}
+	if !strings.Contains(err.Error(), "404") {
+		t.Errorf("error %q should carry the HTTP status", err)
+	}
+}
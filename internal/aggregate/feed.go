@@ -1,24 +1,32 @@
-package main
+package aggregate
 
 import (
+	"bytes"
 	"encoding/xml"
 	"errors"
 	"fmt"
 	"html"
 	"math"
+	"net/url"
 	"os"
 	"path/filepath"
 	"sort"
 	"strconv"
 	"strings"
 	"time"
+
+	"github.com/fzerorubigd/bgg-hotness/internal/history"
 )
 
-// feedCap bounds a feed to its most recent entries by count. It is a nice-to-have — the
-// entries are the product — so it is given up entirely for any run whose sort key could
-// not be fully parsed (see finalizeFeed).
+// feedCap bounds a feed to its most recent entries by count, unless the feed sets its own
+// cap (feedOptions.Cap). Entries past it are not lost: they move to the feed's archive
+// pages (see feed_archive.go). The cap is given up entirely for any run whose sort key
+// could not be fully parsed (see finalizeFeed).
 const feedCap = 200
 
+// noCap is the feedOptions.Cap of a feed that keeps every entry.
+const noCap = -1
+
 // Atom feed output. The aggregate jobs render Atom feeds committed to a dedicated
 // branch; a reader subscribes to a feed's raw URL. There are THREE separate feeds, one
 // file each on the feed branch, so a run of one job cannot touch another's entries and
@@ -35,7 +43,7 @@ const feedCap = 200
 //     when its sort key moved to `updated` for the (then-shared) per-game path.
 //
 // Which shape a run emits is policy, not derivable from the window length, so it is
-// carried by an explicit -per-game flag (see main.go's router). Each feed's id is
+// carried by an explicit -per-game flag (see Run in aggregate.go). Each feed's id is
 // derived from its filename (see feedIDForPath) and its title comes from FEED_TITLE.
 // Three feeds MUST NOT share an <id> — a reader may legitimately dedupe or merge feeds
 // on it — which the per-filename derivation makes structural.
@@ -94,12 +102,22 @@ func feedIDForPath(path string) string {
 }
 
 type atomFeed struct {
-	XMLName xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
-	Title   string      `xml:"title"`
-	ID      string      `xml:"id"`
-	Updated string      `xml:"updated"`
-	Author  atomAuthor  `xml:"author"`
-	Entry   []atomEntry `xml:"entry"`
+	XMLName xml.Name   `xml:"http://www.w3.org/2005/Atom feed"`
+	Title   string     `xml:"title"`
+	ID      string     `xml:"id"`
+	Updated string     `xml:"updated"`
+	Author  atomAuthor `xml:"author"`
+	// Archive marks an RFC 5005 archive page (<fh:archive/>); nil on the subscription
+	// feed, so it marshals to nothing there.
+	Archive *struct{} `xml:"http://purl.org/syndication/history/1.0 archive"`
+	// Link holds the feed-level links. Like atomEntry.Link it is a slice so a feed without
+	// any marshals no element, which keeps a feed that has never archived byte-identical
+	// to one written before archiving existed.
+	Link []atomLink `xml:"link"`
+	// Listed is the per-game feed's record of the games on its last run (see
+	// feed_left.go); empty, and so absent, on a digest feed.
+	Listed string      `xml:"https://github.com/fzerorubigd/bgg-hotness/ns/history listed,omitempty"`
+	Entry  []atomEntry `xml:"entry"`
 }
 
 type atomAuthor struct {
@@ -116,12 +134,19 @@ type atomEntry struct {
 	// has no game to point at (the empty-element defect from #181). Per-game entries
 	// carry exactly one rel="alternate" link — the BGG page — which is the field a
 	// reader's "visit site" navigates to; digest entries carry none.
-	Link    []atomLink  `xml:"link"`
-	Content atomContent `xml:"content"`
+	Link []atomLink `xml:"link"`
+	// Category is a per-game entry's BGG terms (see feed_category.go); nil, and so
+	// absent, on a digest or when BGG returned none.
+	Category []atomCategory `xml:"category"`
+	Content  atomContent    `xml:"content"`
+	// Ranks is a per-game entry's weekly rank history (see feed_history.go), empty and
+	// so absent unless feedOptions.History has been on.
+	Ranks string `xml:"https://github.com/fzerorubigd/bgg-hotness/ns/history ranks,omitempty"`
 }
 
 type atomLink struct {
 	Rel  string `xml:"rel,attr,omitempty"`
+	Type string `xml:"type,attr,omitempty"`
 	Href string `xml:"href,attr"`
 }
 
@@ -133,9 +158,10 @@ type atomContent struct {
 // loadFeed reads the existing feed at path (treating an absent file as an empty feed)
 // and reasserts the canonical feed-level fields so an older or hand-edited feed converges
 // rather than preserving stale values. The feed id is derived from the path (see
-// feedIDForPath); feedTitle is the feed-level title. feed.Updated is deliberately left
-// unset here; finalizeFeed derives it from the entries once they are known.
-func loadFeed(path, feedTitle string) (atomFeed, error) {
+// feedIDForPath); the title and links come from opts (see feedLinks). feed.Updated is
+// deliberately left unset here; finalizeFeed derives it from the entries once they are
+// known.
+func loadFeed(path string, opts feedOptions) (atomFeed, error) {
 	feed := atomFeed{}
 	if b, err := os.ReadFile(path); err == nil {
 		if err := xml.Unmarshal(b, &feed); err != nil {
@@ -147,37 +173,158 @@ func loadFeed(path, feedTitle string) (atomFeed, error) {
 	// Clear XMLName so the struct tag (not the value parsed from disk) supplies the
 	// Atom namespace on marshal.
 	feed.XMLName = xml.Name{}
-	feed.Title = feedTitle
+	feed.Archive = nil
+	feed.Title = opts.Title
 	feed.ID = feedIDForPath(path)
 	feed.Author = atomAuthor{Name: authorName}
+	feed.Link = feedLinks(path, opts)
 	return feed, nil
 }
 
-// saveFeed marshals feed and writes it to path via a temp file + rename, so a crash
-// mid-write cannot leave a truncated feed the next run would fail to parse.
-func saveFeed(path string, feed atomFeed) error {
+// feedLinks is the feed-level links opts configures, in a fixed order so the feed stays
+// byte-stable: rel="self" (the base URL plus the file name), rel="alternate" (the site)
+// and rel="hub" (the WebSub hub). Each is left out when its setting is empty, so an
+// unconfigured feed has no feed-level links beyond the archive ones archiveEntries adds.
+// They are rebuilt from opts on every run rather than kept from disk, so changing or
+// removing a setting takes effect on the next run.
+func feedLinks(path string, opts feedOptions) []atomLink {
+	var links []atomLink
+	if opts.BaseURL != "" {
+		links = append(links, atomLink{Rel: "self", Type: "application/atom+xml", Href: feedURL(opts.BaseURL, path)})
+	}
+	if opts.SiteURL != "" {
+		links = append(links, atomLink{Rel: "alternate", Type: "text/html", Href: opts.SiteURL})
+	}
+	if opts.Hub != "" {
+		links = append(links, atomLink{Rel: "hub", Href: opts.Hub})
+	}
+	return links
+}
+
+// feedURL is the public URL of the file at path under base.
+func feedURL(base, path string) string {
+	return strings.TrimSuffix(base, "/") + "/" + url.PathEscape(filepath.Base(path))
+}
+
+// feedOptions is how a feed is published beyond its entries — its title and the optional
+// formats, links and details — and the run's data those details come from. aggregate
+// builds it once per run from the config, the environment, flags and the BGG lookup;
+// every field's zero value is the plain Atom-plus-JSON feed.
+type feedOptions struct {
+	// Title is the feed-level title (FEED_TITLE).
+	Title string
+	// RSS also writes the feed as RSS 2.0 (see saveRSSFeed).
+	RSS bool
+	// BaseURL is the public URL the feed file is served under (FEED_BASE_URL), SiteURL
+	// the page the feed is about (SITE_URL) and Hub a WebSub hub (WEBSUB_HUB); see
+	// feedLinks.
+	BaseURL, SiteURL, Hub string
+	// History adds each game's rank history to its per-game entry, and Recent is the
+	// recent daily lists its sparkline draws, oldest first (see feed_history.go). It is
+	// opt-in because a listed game's week count then changes its entry every run.
+	History bool
+	Recent  []history.Day
+	// Games is what BGG returned for this run's games, by id (see feed_media.go); a game
+	// missing from it just goes without.
+	Games map[string]gameInfo
+	// Cap is the most entries the feed keeps, feedCap when zero and every entry when
+	// noCap; MaxAge moves out the entries whose sort key is older than it, none when
+	// zero. See finalizeFeed.
+	Cap    int
+	MaxAge time.Duration
+}
+
+// saveFeed marshals feed and writes it to path, then writes the same entries as a JSON
+// Feed next to it (see saveJSONFeed) and, when opts asks for it, as RSS. All of them come
+// from the one finalized feed, so the formats cannot disagree on which entries exist or
+// in what order. Each file is only written when its bytes change, and saveFeed returns
+// the paths it wrote: an unchanged run is a no-op on disk, and the caller announces only
+// the files that really changed (see aggregate -changed). A run that only turns on RSS
+// writes, and announces, the .rss alone.
+func saveFeed(path string, feed atomFeed, opts feedOptions) ([]string, error) {
+	out, err := marshalFeed(feed)
+	if err != nil {
+		return nil, err
+	}
+	var written []string
+	if w, err := writeIfChanged(path, out); err != nil {
+		return nil, err
+	} else if w {
+		written = append(written, path)
+	}
+	if jsonPath := jsonFeedPath(path); jsonPath != path {
+		w, err := saveJSONFeed(jsonPath, feed)
+		if err != nil {
+			return nil, err
+		}
+		if w {
+			written = append(written, jsonPath)
+		}
+	}
+	if rssPath := rssFeedPath(path); opts.RSS && rssPath != path {
+		w, err := saveRSSFeed(rssPath, feed)
+		if err != nil {
+			return nil, err
+		}
+		if w {
+			written = append(written, rssPath)
+		}
+	}
+	return written, nil
+}
+
+// feedTopics is the public URL of each of the written feed files, the topics a WebSub
+// hub is told about. It is empty without a base URL, as the files then have no known
+// public URL.
+func feedTopics(written []string, opts feedOptions) []string {
+	if opts.BaseURL == "" {
+		return nil
+	}
+	topics := make([]string, len(written))
+	for i, path := range written {
+		topics[i] = feedURL(opts.BaseURL, path)
+	}
+	return topics
+}
+
+// marshalFeed renders feed as an Atom document.
+func marshalFeed(feed atomFeed) ([]byte, error) {
 	out, err := xml.MarshalIndent(&feed, "", "  ")
 	if err != nil {
-		return fmt.Errorf("marshal feed: %w", err)
+		return nil, fmt.Errorf("marshal feed: %w", err)
 	}
-	out = append([]byte(xml.Header), append(out, '\n')...)
+	return append([]byte(xml.Header), append(out, '\n')...), nil
+}
+
+// writeAtomic writes b to path via a temp file + rename, so a crash mid-write cannot
+// leave a truncated feed the next run would fail to parse.
+func writeAtomic(path string, b []byte) error {
 	tmp := path + ".tmp"
-	if err := os.WriteFile(tmp, out, 0o644); err != nil {
+	if err := os.WriteFile(tmp, b, 0o644); err != nil {
 		return err
 	}
 	return os.Rename(tmp, path)
 }
 
+// writeIfChanged writes b to path with writeAtomic unless the file already holds exactly
+// b, and reports whether it wrote.
+func writeIfChanged(path string, b []byte) (bool, error) {
+	if old, err := os.ReadFile(path); err == nil && bytes.Equal(old, b) {
+		return false, nil
+	}
+	return true, writeAtomic(path, b)
+}
+
 // updateFeedDigest inserts or replaces the single entry for this run and writes the
-// result — the monthly (feed-monthly.xml) and yearly (feed-yearly.xml) jobs. feedTitle is
+// result — the monthly (feed-monthly.xml) and yearly (feed-yearly.xml) jobs. opts carries
 // the feed-level title; entryTitle is this run's title (the entry id keys off it).
 // published is the end of the aggregated period; updated is generation time. Digest feeds
 // sort by published (see finalizeFeed's sortByPublished). rows are the ranked game rows
-// [rank, id, wins, link, name].
-func updateFeedDigest(path, feedTitle, entryTitle string, published, updated time.Time, rows [][]string) error {
-	feed, err := loadFeed(path, feedTitle)
+// [rank, id, wins, link, name]. It returns the feed files it changed (see saveFeed).
+func updateFeedDigest(path string, opts feedOptions, entryTitle string, published, updated time.Time, rows [][]string) ([]string, error) {
+	feed, err := loadFeed(path, opts)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	entry := atomEntry{
@@ -185,7 +332,7 @@ func updateFeedDigest(path, feedTitle, entryTitle string, published, updated tim
 		ID:        tagPrefix + slug(entryTitle),
 		Published: published.UTC().Format(time.RFC3339),
 		Updated:   updated.UTC().Format(time.RFC3339),
-		Content:   atomContent{Type: "html", Text: renderContent(rows)},
+		Content:   atomContent{Type: "html", Text: renderContent(rows, opts.Games)},
 		// Link intentionally nil: a digest aggregates many games and has no single
 		// page to link to.
 	}
@@ -206,20 +353,23 @@ func updateFeedDigest(path, feedTitle, entryTitle string, published, updated tim
 	}
 
 	// Digest feeds carry no per-game ranks (nil tie-break) and sort by published.
-	finalizeFeed(&feed, nil, updated, true)
-	return saveFeed(path, feed)
+	evicted := finalizeFeed(&feed, nil, updated, true, opts.Cap, opts.MaxAge)
+	if err := archiveEntries(path, &feed, evicted, updated); err != nil {
+		return nil, err
+	}
+	return saveFeed(path, feed, opts)
 }
 
 // updateFeedPerGame upserts one entry per game row (the weekly feed.xml job). Each game's
 // entry is keyed on its numeric BGG id and updated in place across runs: published is the
 // first-seen instant and is preserved, updated advances only when the rendered content
 // actually changes, and every entry carries a rel="alternate" <link> to its BGG page.
-// feedTitle is the feed-level title; now is this run's generation instant. rows are
-// [rank, id, wins, link, name].
-func updateFeedPerGame(path, feedTitle string, now time.Time, rows [][]string) error {
-	feed, err := loadFeed(path, feedTitle)
+// opts carries the feed-level title; now is this run's generation instant. rows are
+// [rank, id, wins, link, name]. It returns the feed files it changed (see saveFeed).
+func updateFeedPerGame(path string, opts feedOptions, now time.Time, rows [][]string) ([]string, error) {
+	feed, err := loadFeed(path, opts)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	nowStr := now.UTC().Format(time.RFC3339)
 
@@ -231,6 +381,11 @@ func updateFeedPerGame(path, feedTitle string, now time.Time, rows [][]string) e
 	// It is never persisted to the feed, so a stale rank from an earlier cohort cannot
 	// exist to be mis-compared (finalizeFeed relies on this).
 	rankByID := make(map[string]int, len(rows))
+	// wasListed is the last run's list, when the feed recorded one, and listed this
+	// run's; see feed_left.go.
+	wasListed := parseListed(feed.Listed)
+	listed := make(map[string]listing, len(rows))
+	today := now.UTC().Format(time.DateOnly)
 
 	for _, r := range rows {
 		if len(r) < 5 {
@@ -238,15 +393,18 @@ func updateFeedPerGame(path, feedTitle string, now time.Time, rows [][]string) e
 		}
 		rank, id, wins, link, name := r[0], r[1], r[2], r[3], r[4]
 		entryID := tagPrefix + gamePrefix + id
-		if n, err := strconv.Atoi(rank); err == nil {
+		n, err := strconv.Atoi(rank)
+		if err == nil {
 			rankByID[entryID] = n
 		}
+		prev, ok := wasListed[entryID]
+		listed[entryID] = continueListing(prev, ok, today, n)
 		idx, exists := byID[entryID]
 
 		title := name
 		if title == "" {
 			if exists {
-				// Sticky title: a blank name is a transient upstream miss (main.go
+				// Sticky title: a blank name is a transient upstream miss (rankedRows
 				// emits the row with the known id and a blank name rather than
 				// panicking). Keep the established title so a Wingspan -> BGG #id ->
 				// Wingspan flap from a fetch hiccup does not advance updated and
@@ -261,6 +419,40 @@ func updateFeedPerGame(path, feedTitle string, now time.Time, rows [][]string) e
 		}
 		content := atomContent{Type: "html", Text: renderGameContent(rank, wins)}
 		links := []atomLink{{Rel: "alternate", Href: link}}
+		// The thumbnail is sticky like the title.
+		thumb := opts.Games[id].Thumbnail
+		if thumb == "" && exists {
+			thumb = enclosure(feed.Entry[idx].Link)
+		}
+		if thumb != "" {
+			content.Text = "<p>" + renderThumbnail(thumb, title) + "</p>" + content.Text
+			links = append(links, enclosureLink(thumb))
+		}
+		// So are the categories.
+		cats := opts.Games[id].Categories
+		if len(cats) == 0 && exists {
+			cats = feed.Entry[idx].Category
+		}
+		var ranks, published string
+		if exists {
+			ranks, published = feed.Entry[idx].Ranks, feed.Entry[idx].Published
+		} else {
+			published = nowStr
+		}
+		if opts.History {
+			recs := parseRanks(ranks)
+			if n, err := strconv.Atoi(rank); err == nil {
+				recs = recordRank(recs, now.UTC().Format(time.DateOnly), n)
+			}
+			ranks = formatRanks(recs)
+			// A feed that has not recorded the list yet cannot tell where the stretch
+			// began, so it counts every recorded rank rather than starting them all over.
+			since := listed[entryID].Since
+			if feed.Listed == "" {
+				since = ""
+			}
+			content.Text += renderGameHistory(recs, since, published, dailyRanks(opts.Recent, id))
+		}
 
 		if exists {
 			e := &feed.Entry[idx]
@@ -270,12 +462,14 @@ func updateFeedPerGame(path, feedTitle string, now time.Time, rows [][]string) e
 			// and make the carry-forward a silent no-op. published is preserved
 			// untouched — a wholesale replace would reset first-seen to now every week,
 			// pinning a long-hot game to the top and re-notifying readers each run.
-			if e.Title != title || e.Content.Text != content.Text || !sameLinks(e.Link, links) {
+			if e.Title != title || e.Content.Text != content.Text || !sameLinks(e.Link, links) || !sameCategories(e.Category, cats) || e.Ranks != ranks {
 				e.Updated = nowStr
 			}
 			e.Title = title
 			e.Content = content
 			e.Link = links
+			e.Category = cats
+			e.Ranks = ranks
 		} else {
 			feed.Entry = append(feed.Entry, atomEntry{
 				Title:     title,
@@ -283,7 +477,9 @@ func updateFeedPerGame(path, feedTitle string, now time.Time, rows [][]string) e
 				Published: nowStr,
 				Updated:   nowStr,
 				Link:      links,
+				Category:  cats,
 				Content:   content,
+				Ranks:     ranks,
 			})
 			// Register the new entry's index so a LATER row with the same id in THIS
 			// run updates it in place rather than appending a second entry with a
@@ -297,13 +493,37 @@ func updateFeedPerGame(path, feedTitle string, now time.Time, rows [][]string) e
 		}
 	}
 
+	// A game listed last run and not this one gets its one "dropped out" update. One
+	// whose entry has already been archived has nothing left to update. The thumbnail
+	// stays at the top of the body, as it is on a listed game's entry, from the
+	// enclosure the entry already links.
+	for entryID, l := range wasListed {
+		if _, still := listed[entryID]; still {
+			continue
+		}
+		if idx, ok := byID[entryID]; ok {
+			e := &feed.Entry[idx]
+			text := renderLeftContent(l, now)
+			if thumb := enclosure(e.Link); thumb != "" {
+				text = "<p>" + renderThumbnail(thumb, e.Title) + "</p>" + text
+			}
+			e.Content = atomContent{Type: "html", Text: text}
+			e.Updated = nowStr
+		}
+	}
+	feed.Listed = formatListed(listed)
+
 	// Per-game feeds sort by updated (freshness); rankByID breaks ties within a run.
-	finalizeFeed(&feed, rankByID, now, false)
-	return saveFeed(path, feed)
+	evicted := finalizeFeed(&feed, rankByID, now, false, opts.Cap, opts.MaxAge)
+	if err := archiveEntries(path, &feed, evicted, now); err != nil {
+		return nil, err
+	}
+	return saveFeed(path, feed, opts)
 }
 
-// finalizeFeed orders entries newest-first, caps by count, and sets the feed-level
-// updated.
+// finalizeFeed orders entries newest-first, caps by count and by age, and sets the
+// feed-level updated. It returns the entries the caps evicted, oldest last, for the
+// caller to archive.
 //
 // sortByPublished selects the PRIMARY sort key for the WHOLE feed — chosen once per feed,
 // never per entry:
@@ -323,9 +543,15 @@ func updateFeedPerGame(path, feedTitle string, now time.Time, rows [][]string) e
 // yields an unspecified permutation with nothing failing. The three-feed split exists
 // partly so this choice never has to be per-entry.
 //
+// The age cap runs on the same sort key, so both caps cut the old end of the list: a
+// per-game entry not updated in maxAge goes, and a digest whose period ended more than
+// maxAge ago. An entry for a game on this run's list is never too old, though: its game
+// is still hot, and moving it out would bring it straight back next run as a new entry.
+//
 // rankByID maps an entry id to its rank in THIS run (nil on the digest path). now is the
-// fallback feed-updated for an empty feed.
-func finalizeFeed(feed *atomFeed, rankByID map[string]int, now time.Time, sortByPublished bool) {
+// fallback feed-updated for an empty feed and the instant the age cap counts back from.
+// limit and maxAge are feedOptions.Cap and feedOptions.MaxAge.
+func finalizeFeed(feed *atomFeed, rankByID map[string]int, now time.Time, sortByPublished bool, limit int, maxAge time.Duration) []atomEntry {
 	type keyedEntry struct {
 		entry atomEntry
 		when  time.Time
@@ -393,17 +619,28 @@ func finalizeFeed(feed *atomFeed, rankByID map[string]int, now time.Time, sortBy
 		return rankOf(keyed[i].entry.ID) < rankOf(keyed[j].entry.ID)
 	})
 
-	// Cap by count, but skip truncation entirely for any run where a key failed to
+	// Cap by count and age, but skip both entirely for any run where a key failed to
 	// parse — the skip is per RUN, not per entry: dropping "only" the sorted tail still
 	// deletes on an incomplete ordering. When skipped, the feed drifts over the cap
 	// until the stderr line is acted on; nothing is lost.
-	keep := len(keyed)
-	if capSafe && keep > feedCap {
-		keep = feedCap
+	if limit == 0 {
+		limit = feedCap
 	}
-	feed.Entry = make([]atomEntry, keep)
-	for i := 0; i < keep; i++ {
-		feed.Entry[i] = keyed[i].entry
+	var cutoff time.Time
+	if maxAge > 0 {
+		cutoff = now.Add(-maxAge)
+	}
+	feed.Entry = make([]atomEntry, 0, len(keyed))
+	var evicted []atomEntry
+	for _, k := range keyed {
+		full := limit > 0 && len(feed.Entry) >= limit
+		_, listed := rankByID[k.entry.ID]
+		stale := !cutoff.IsZero() && k.when.Before(cutoff) && !listed
+		if capSafe && (full || stale) {
+			evicted = append(evicted, k.entry)
+			continue
+		}
+		feed.Entry = append(feed.Entry, k.entry)
 	}
 
 	// Feed-level updated is the most recent entry updated, so a run that changes no entry
@@ -414,6 +651,7 @@ func finalizeFeed(feed *atomFeed, rankByID map[string]int, now time.Time, sortBy
 	} else {
 		feed.Updated = maxUpdated.UTC().Format(time.RFC3339)
 	}
+	return evicted
 }
 
 // sameLinks reports whether two link slices are element-wise equal. It is the change
@@ -435,7 +673,7 @@ func sameLinks(a, b []atomLink) bool {
 // BGG link. It is emitted as Atom content type="html"; encoding/xml escapes the whole
 // string once as chardata, so the inner HTML is additionally html-escaped here to stay
 // well-formed after a reader un-escapes it.
-func renderContent(rows [][]string) string {
+func renderContent(rows [][]string, games map[string]gameInfo) string {
 	var b strings.Builder
 	b.WriteString("<ol>")
 	for _, r := range rows {
@@ -448,8 +686,13 @@ func renderContent(rows [][]string) string {
 			// an empty link so the entry stays legible.
 			name = "BGG #" + r[1]
 		}
+		if img := renderThumbnail(games[r[1]].Thumbnail, ""); img != "" {
+			name = img + " " + html.EscapeString(name)
+		} else {
+			name = html.EscapeString(name)
+		}
 		fmt.Fprintf(&b, "<li><a href=\"%s\">%s</a> — %s wins</li>",
-			html.EscapeString(r[3]), html.EscapeString(name), html.EscapeString(r[2]))
+			html.EscapeString(r[3]), name, html.EscapeString(r[2]))
 	}
 	b.WriteString("</ol>")
 	return b.String()
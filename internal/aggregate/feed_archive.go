@@ -0,0 +1,242 @@
+package aggregate
+
+import (
+	"encoding/xml"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Archived feeds (RFC 5005). An entry the cap evicts from a feed is not dropped: it moves
+// to an archive page next to the feed, one per run that evicted anything, named after the
+// feed file, the month of the run and the run's place among that month's pages —
+// feed.xml -> feed-archive-2026-10-1.xml, feed-archive-2026-10-2.xml, ...;
+// feed-monthly.xml -> feed-monthly-archive-2026-10-1.xml. The pages and the feed link up
+// as RFC 5005 lays out:
+//
+//   - the feed (the subscription document) links its newest page as rel="prev-archive";
+//   - each page is marked <fh:archive/>, links the feed as rel="current" and its
+//     neighbours as rel="prev-archive" and rel="next-archive".
+//
+// A reader or the site generator starts at the feed and follows prev-archive back to the
+// first page to see every entry the feed ever held.
+//
+// A page's entries are final the moment it is written. RFC 5005 (section 4.2) has the
+// set of entries at an archive URI stay the same, so a reader that fetched a page once
+// need never fetch it again; a later eviction, in the same month or not and even of an
+// entry an older page already holds, writes a new page rather than adding to one. The one
+// later change to a page is its links: next-archive is added when the next page is
+// created. Pages are named after the run that wrote them, not the entries' own dates, so
+// an old digest period regenerated and evicted again lands on a new page too.
+//
+// Pages written before this rule, one per month (feed-archive-2026-10.xml), are still
+// read as part of the chain, as the first page of their month.
+//
+// A page carries the feed's id and title — it is the same logical feed, split for size.
+// An id can appear more than once across the feed and its pages (a per-game entry evicted
+// while a game was quiet comes back as the same game:<id> when it returns); RFC 5005
+// has readers keep the copy with the latest updated, which is the live one.
+//
+// Links are relative to the feed file, so they resolve wherever the feed branch is
+// served from.
+
+// Link relations RFC 5005 defines for paging.
+const (
+	relPrevArchive = "prev-archive"
+	relNextArchive = "next-archive"
+	relCurrent     = "current"
+)
+
+// archivePagePath is the n-th archive page of the feed at path for the month of t.
+func archivePagePath(path string, t time.Time, n int) string {
+	ext := filepath.Ext(path)
+	return strings.TrimSuffix(path, ext) + "-archive-" + t.UTC().Format("2006-01") + "-" + strconv.Itoa(n) + ext
+}
+
+// archivePage is a page's place in the chain: its month, YYYY-MM, and its number in the
+// month, 0 for a page from before the pages were numbered.
+type archivePage struct {
+	path  string
+	month string
+	n     int
+}
+
+// parseArchivePage reads the month and number out of the name of a page of the feed at
+// path; ok is false when name is not one.
+func parseArchivePage(path, name string) (archivePage, bool) {
+	ext := filepath.Ext(path)
+	rest, ok := strings.CutPrefix(name, strings.TrimSuffix(path, ext)+"-archive-")
+	if !ok || !strings.HasSuffix(rest, ext) {
+		return archivePage{}, false
+	}
+	rest = strings.TrimSuffix(rest, ext)
+	if len(rest) < len("2006-01") {
+		return archivePage{}, false
+	}
+	month, num := rest[:len("2006-01")], rest[len("2006-01"):]
+	if _, err := time.Parse("2006-01", month); err != nil {
+		return archivePage{}, false
+	}
+	p := archivePage{path: name, month: month}
+	if num == "" {
+		return p, true
+	}
+	// -N with no sign or leading zero, so one number has one name.
+	n, err := strconv.Atoi(strings.TrimPrefix(num, "-"))
+	if err != nil || n < 1 || num != "-"+strconv.Itoa(n) {
+		return archivePage{}, false
+	}
+	p.n = n
+	return p, true
+}
+
+// archivePages lists the archive pages of the feed at path on disk, oldest first. A file
+// whose name does not end in YYYY-MM-N, or the older YYYY-MM, is not a page.
+func archivePages(path string) ([]string, error) {
+	found, err := findArchivePages(path)
+	if err != nil {
+		return nil, err
+	}
+	pages := make([]string, len(found))
+	for i, p := range found {
+		pages[i] = p.path
+	}
+	return pages, nil
+}
+
+func findArchivePages(path string) ([]archivePage, error) {
+	ext := filepath.Ext(path)
+	matches, err := filepath.Glob(globEscape(strings.TrimSuffix(path, ext)+"-archive-") + "*" + globEscape(ext))
+	if err != nil {
+		return nil, err
+	}
+	var pages []archivePage
+	for _, m := range matches {
+		if p, ok := parseArchivePage(path, m); ok {
+			pages = append(pages, p)
+		}
+	}
+	// YYYY-MM sorts chronologically as a string; the number within a month does not
+	// (10 < 9), so it is compared as one.
+	sort.Slice(pages, func(i, j int) bool {
+		if pages[i].month != pages[j].month {
+			return pages[i].month < pages[j].month
+		}
+		return pages[i].n < pages[j].n
+	})
+	return pages, nil
+}
+
+// nextArchivePage is the path of the page a run at now writes: the month's next number.
+func nextArchivePage(path string, now time.Time) (string, error) {
+	pages, err := findArchivePages(path)
+	if err != nil {
+		return "", err
+	}
+	month, n := now.UTC().Format("2006-01"), 0
+	for _, p := range pages {
+		if p.month == month {
+			n = max(n, p.n)
+		}
+	}
+	return archivePagePath(path, now, n+1), nil
+}
+
+// globEscape quotes the glob metacharacters in s so filepath.Glob matches it literally.
+func globEscape(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "*", `\*`, "?", `\?`, "[", `\[`).Replace(s)
+}
+
+// archiveEntries writes evicted, the entries finalizeFeed cut from the feed at path, to a
+// new archive page, then relinks every page and points feed at the newest one. evicted
+// comes in the feed's order, newest first on its sort key, which the page keeps. It runs
+// on every update, evictions or not, so the feed's prev-archive link is reasserted from
+// the pages on disk the way loadFeed reasserts the title and id.
+func archiveEntries(path string, feed *atomFeed, evicted []atomEntry, now time.Time) error {
+	if len(evicted) > 0 {
+		page, err := nextArchivePage(path, now)
+		if err != nil {
+			return err
+		}
+		if err := saveArchivePage(page, atomFeed{Entry: evicted}, feed); err != nil {
+			return err
+		}
+	}
+
+	pages, err := archivePages(path)
+	if err != nil {
+		return err
+	}
+	for i, page := range pages {
+		arch, err := loadArchivePage(page)
+		if err != nil {
+			return err
+		}
+		arch.Link = []atomLink{{Rel: relCurrent, Href: filepath.Base(path)}}
+		if i > 0 {
+			arch.Link = append(arch.Link, atomLink{Rel: relPrevArchive, Href: filepath.Base(pages[i-1])})
+		}
+		if i < len(pages)-1 {
+			arch.Link = append(arch.Link, atomLink{Rel: relNextArchive, Href: filepath.Base(pages[i+1])})
+		}
+		if err := saveArchivePage(page, arch, feed); err != nil {
+			return err
+		}
+	}
+
+	links := feed.Link[:0:0]
+	for _, l := range feed.Link {
+		if l.Rel != relPrevArchive && l.Rel != relNextArchive && l.Rel != relCurrent {
+			links = append(links, l)
+		}
+	}
+	if len(pages) > 0 {
+		links = append(links, atomLink{Rel: relPrevArchive, Href: filepath.Base(pages[len(pages)-1])})
+	}
+	feed.Link = links
+	return nil
+}
+
+// loadArchivePage reads the archive page at path, to relink it.
+func loadArchivePage(path string) (atomFeed, error) {
+	var arch atomFeed
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return arch, fmt.Errorf("read archive page %q: %w", path, err)
+	}
+	if err := xml.Unmarshal(b, &arch); err != nil {
+		return arch, fmt.Errorf("parse archive page %q: %w", path, err)
+	}
+	arch.XMLName = xml.Name{}
+	return arch, nil
+}
+
+// saveArchivePage writes arch to path under feed's title, id and author, with updated the
+// latest entry updated so an untouched page stays byte-identical. It only writes when the
+// bytes change.
+func saveArchivePage(path string, arch atomFeed, feed *atomFeed) error {
+	arch.Title = feed.Title
+	arch.ID = feed.ID
+	arch.Author = feed.Author
+	arch.Archive = &struct{}{}
+	var maxUpdated time.Time
+	for _, e := range arch.Entry {
+		if u, err := time.Parse(time.RFC3339, e.Updated); err == nil && u.After(maxUpdated) {
+			maxUpdated = u
+		}
+	}
+	arch.Updated = feed.Updated
+	if !maxUpdated.IsZero() {
+		arch.Updated = maxUpdated.UTC().Format(time.RFC3339)
+	}
+	b, err := marshalFeed(arch)
+	if err != nil {
+		return err
+	}
+	_, err = writeIfChanged(path, b)
+	return err
+}
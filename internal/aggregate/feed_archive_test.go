@@ -0,0 +1,165 @@
+package aggregate
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"testing"
+	"time"
+)
+
+// Runs past the cap across two months: nothing is lost, each run that evicts writes its
+// own page, numbered within the month it ran in, and the feed and pages link up per
+// RFC 5005.
+func TestArchiveKeepsEvictedEntries(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "feed.xml")
+	opts := feedOptions{Title: testFeedTitle, Cap: 3}
+	day := func(month time.Month, d int) time.Time { return time.Date(2026, month, d, 9, 0, 0, 0, time.UTC) }
+	runs := []time.Time{day(10, 1), day(10, 2), day(10, 3), day(10, 4), day(10, 5), day(11, 1)}
+	for i, when := range runs {
+		if _, err := updateFeedDigest(path, opts, fmt.Sprintf("run-%04d", i), when, when, sampleRows()); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	oct1, oct2 := filepath.Join(dir, "feed-archive-2026-10-1.xml"), filepath.Join(dir, "feed-archive-2026-10-2.xml")
+	nov1 := filepath.Join(dir, "feed-archive-2026-11-1.xml")
+	pages, err := archivePages(path)
+	if err != nil || !slices.Equal(pages, []string{oct1, oct2, nov1}) {
+		t.Fatalf("archivePages = %v, %v", pages, err)
+	}
+
+	feed := parseFeed(t, path)
+	if len(feed.Entry) != 3 || feed.Archive != nil {
+		t.Errorf("feed has %d entries, archive marker %v", len(feed.Entry), feed.Archive)
+	}
+	if want := []atomLink{{Rel: relPrevArchive, Href: "feed-archive-2026-11-1.xml"}}; !sameLinks(feed.Link, want) {
+		t.Errorf("feed links = %+v, want %+v", feed.Link, want)
+	}
+
+	docs := []atomFeed{feed}
+	for _, c := range []struct {
+		page string
+		want []atomLink
+	}{
+		{oct1, []atomLink{{Rel: relCurrent, Href: "feed.xml"}, {Rel: relNextArchive, Href: "feed-archive-2026-10-2.xml"}}},
+		{oct2, []atomLink{{Rel: relCurrent, Href: "feed.xml"}, {Rel: relPrevArchive, Href: "feed-archive-2026-10-1.xml"}, {Rel: relNextArchive, Href: "feed-archive-2026-11-1.xml"}}},
+		{nov1, []atomLink{{Rel: relCurrent, Href: "feed.xml"}, {Rel: relPrevArchive, Href: "feed-archive-2026-10-2.xml"}}},
+	} {
+		page := parseFeed(t, c.page)
+		if page.Archive == nil || page.ID != feed.ID || page.Title != feed.Title {
+			t.Errorf("%s header = %q %q, archive marker %v", filepath.Base(c.page), page.ID, page.Title, page.Archive)
+		}
+		if !sameLinks(page.Link, c.want) {
+			t.Errorf("%s links = %+v, want %+v", filepath.Base(c.page), page.Link, c.want)
+		}
+		docs = append(docs, page)
+	}
+
+	// Every run is in exactly one document, the oldest on the first page.
+	seen := map[string]int{}
+	for _, doc := range docs {
+		for _, e := range doc.Entry {
+			seen[e.Title]++
+		}
+	}
+	for i := range runs {
+		if n := seen[fmt.Sprintf("run-%04d", i)]; n != 1 {
+			t.Errorf("run-%04d appears %d times", i, n)
+		}
+	}
+	if got := entryIDs(docs[1]); len(got) != 1 || got[0] != tagPrefix+"run-0000" {
+		t.Errorf("first page = %v, want the oldest run alone", got)
+	}
+
+	// An unchanged re-run touches none of the files.
+	before := map[string][]byte{}
+	for _, p := range append([]string{path}, pages...) {
+		b, err := os.ReadFile(p)
+		if err != nil {
+			t.Fatal(err)
+		}
+		before[p] = b
+	}
+	last := runs[len(runs)-1]
+	if _, err := updateFeedDigest(path, opts, fmt.Sprintf("run-%04d", len(runs)-1), last, last, sampleRows()); err != nil {
+		t.Fatal(err)
+	}
+	for p, b := range before {
+		if after, _ := os.ReadFile(p); !bytes.Equal(after, b) {
+			t.Errorf("%s changed on an unchanged re-run", filepath.Base(p))
+		}
+	}
+}
+
+// A page's entries never change once written (RFC 5005 section 4.2): a second eviction
+// in the same month writes a new page, even when it evicts an entry the first page
+// already holds, and the first page only gains its next-archive link.
+func TestArchivePagesAreImmutable(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "feed.xml")
+	opts := feedOptions{Title: testFeedTitle, Cap: 2}
+	day := func(d int) time.Time { return time.Date(2026, 10, d, 9, 0, 0, 0, time.UTC) }
+	for i := range 3 {
+		if _, err := updateFeedDigest(path, opts, fmt.Sprintf("run-%04d", i), day(i+1), day(i+1), sampleRows()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	first := filepath.Join(dir, "feed-archive-2026-10-1.xml")
+	before := parseFeed(t, first)
+	if got := entryIDs(before); len(got) != 1 || got[0] != tagPrefix+"run-0000" {
+		t.Fatalf("first page = %v", got)
+	}
+
+	// Regenerate the archived period: it comes back at its old place, the end, and is
+	// evicted again the same day, together with nothing else.
+	if _, err := updateFeedDigest(path, opts, "run-0000", day(1), day(10), sampleRows()); err != nil {
+		t.Fatal(err)
+	}
+	after := parseFeed(t, first)
+	if !reflect.DeepEqual(after.Entry, before.Entry) {
+		t.Errorf("first page entries changed:\n%+v\n%+v", before.Entry, after.Entry)
+	}
+	if want := []atomLink{{Rel: relCurrent, Href: "feed.xml"}, {Rel: relNextArchive, Href: "feed-archive-2026-10-2.xml"}}; !sameLinks(after.Link, want) {
+		t.Errorf("first page links = %+v, want %+v", after.Link, want)
+	}
+	second := parseFeed(t, filepath.Join(dir, "feed-archive-2026-10-2.xml"))
+	if got := entryIDs(second); len(got) != 1 || got[0] != tagPrefix+"run-0000" || second.Entry[0].Updated != day(10).Format(time.RFC3339) {
+		t.Errorf("second page = %+v, want the regenerated run-0000", second.Entry)
+	}
+}
+
+// Pages belong to their feed alone: the monthly feed's pages are not the weekly feed's,
+// though one name is a prefix of the other.
+func TestArchivePagesPerFeed(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{
+		"feed-archive-2026-10-10.xml", "feed-archive-2026-10-9.xml", "feed-archive-2026-10.xml", "feed-archive-2026-09-1.xml",
+		"feed-archive-2026-10-01.xml", "feed-monthly-archive-2026-09-1.xml", "feed-archive-notes.xml", "feed-archive-2026-10-1.json",
+	} {
+		if err := os.WriteFile(filepath.Join(dir, name), nil, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	var names []string
+	pages, err := archivePages(filepath.Join(dir, "feed.xml"))
+	for _, p := range pages {
+		names = append(names, filepath.Base(p))
+	}
+	// The unnumbered page of a month, from before the pages were numbered, comes first in
+	// it, and 10 comes after 9.
+	if want := []string{"feed-archive-2026-09-1.xml", "feed-archive-2026-10.xml", "feed-archive-2026-10-9.xml", "feed-archive-2026-10-10.xml"}; err != nil || !slices.Equal(names, want) {
+		t.Errorf("weekly pages = %v, %v; want %v", names, err, want)
+	}
+	pages, err = archivePages(filepath.Join(dir, "feed-monthly.xml"))
+	if err != nil || len(pages) != 1 || filepath.Base(pages[0]) != "feed-monthly-archive-2026-09-1.xml" {
+		t.Errorf("monthly pages = %v, %v", pages, err)
+	}
+	if next, err := nextArchivePage(filepath.Join(dir, "feed.xml"), time.Date(2026, 10, 31, 0, 0, 0, 0, time.UTC)); err != nil || filepath.Base(next) != "feed-archive-2026-10-11.xml" {
+		t.Errorf("next page = %q, %v", next, err)
+	}
+}
@@ -1,4 +1,4 @@
-package main
+package aggregate
 
 import (
 	"encoding/xml"
@@ -30,7 +30,7 @@ func TestUpdateFeedCapsByCount(t *testing.T) {
 	total := feedCap + 5
 	for i := 0; i < total; i++ {
 		pub := base.Add(time.Duration(i) * time.Hour) // strictly increasing
-		if err := updateFeedDigest(path, testFeedTitle, fmt.Sprintf("run-%04d", i), pub, pub, sampleRows()); err != nil {
+		if _, err := updateFeedDigest(path, testOpts, fmt.Sprintf("run-%04d", i), pub, pub, sampleRows()); err != nil {
 			t.Fatal(err)
 		}
 	}
@@ -58,10 +58,10 @@ func TestUpdateFeedDigestSortsByPublished(t *testing.T) {
 	genEarly := time.Date(2026, 8, 13, 0, 0, 0, 0, time.UTC)
 	genLate := time.Date(2026, 8, 20, 0, 0, 0, 0, time.UTC)
 
-	if err := updateFeedDigest(path, testFeedTitle, "2026-08-01_14-days", recentPub, genEarly, sampleRows()); err != nil {
+	if _, err := updateFeedDigest(path, testOpts, "2026-08-01_14-days", recentPub, genEarly, sampleRows()); err != nil {
 		t.Fatal(err)
 	}
-	if err := updateFeedDigest(path, testFeedTitle, "Yearly - 2024", oldPub, genLate, sampleRows()); err != nil {
+	if _, err := updateFeedDigest(path, testOpts, "Yearly - 2024", oldPub, genLate, sampleRows()); err != nil {
 		t.Fatal(err)
 	}
 
@@ -90,7 +90,7 @@ func TestUpdateFeedReplaceNoDuplicateAfterSort(t *testing.T) {
 		{"2026-06-01_14-days", pubB, pubB},
 		{"Yearly - 2025", pubA, pubA.Add(48 * time.Hour)}, // re-dispatch, later gen
 	} {
-		if err := updateFeedDigest(path, testFeedTitle, s.title, s.pub, s.gen, sampleRows()); err != nil {
+		if _, err := updateFeedDigest(path, testOpts, s.title, s.pub, s.gen, sampleRows()); err != nil {
 			t.Fatal(err)
 		}
 	}
@@ -118,10 +118,10 @@ func TestUpdateFeedReAddBelowCapSurvives(t *testing.T) {
 	path := filepath.Join(t.TempDir(), "feed.xml")
 	base := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
 
-	if err := updateFeedDigest(path, testFeedTitle, "2026-02-01_14-days", base.Add(31*24*time.Hour), base, sampleRows()); err != nil {
+	if _, err := updateFeedDigest(path, testOpts, "2026-02-01_14-days", base.Add(31*24*time.Hour), base, sampleRows()); err != nil {
 		t.Fatal(err)
 	}
-	if err := updateFeedDigest(path, testFeedTitle, "Yearly - 2025", base, base.Add(time.Hour), sampleRows()); err != nil {
+	if _, err := updateFeedDigest(path, testOpts, "Yearly - 2025", base, base.Add(time.Hour), sampleRows()); err != nil {
 		t.Fatal(err)
 	}
 
@@ -151,7 +151,7 @@ func TestUpdateFeedDigestSkipsTruncationOnUnparseablePublished(t *testing.T) {
 	writeRawFeed(t, path, entries)
 
 	newTS := time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC)
-	if err := updateFeedDigest(path, testFeedTitle, "run-new", newTS, newTS, sampleRows()); err != nil {
+	if _, err := updateFeedDigest(path, testOpts, "run-new", newTS, newTS, sampleRows()); err != nil {
 		t.Fatal(err)
 	}
 
@@ -206,7 +206,7 @@ func TestUpdateFeedPerGameSkipsTruncationOnUnparseableUpdated(t *testing.T) {
 	// feedCap+2. With one updated unparseable the cap is skipped, so nothing is cut.
 	now := time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC)
 	rows := [][]string{{"1", "9999", "5", "https://boardgamegeek.com/boardgame/9999", "New Game"}}
-	if err := updateFeedPerGame(path, testFeedTitle, now, rows); err != nil {
+	if _, err := updateFeedPerGame(path, testOpts, now, rows); err != nil {
 		t.Fatal(err)
 	}
 
@@ -234,7 +234,7 @@ func TestUpdateFeedPerGameTruncatesWhenAllParseable(t *testing.T) {
 
 	now := time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC)
 	rows := [][]string{{"1", "9999", "5", "https://boardgamegeek.com/boardgame/9999", "New Game"}}
-	if err := updateFeedPerGame(path, testFeedTitle, now, rows); err != nil {
+	if _, err := updateFeedPerGame(path, testOpts, now, rows); err != nil {
 		t.Fatal(err)
 	}
 
@@ -264,7 +264,7 @@ func TestUpdateFeedDigestTruncatesWhenAllParseable(t *testing.T) {
 	writeRawFeed(t, path, entries)
 
 	newPub := time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC)
-	if err := updateFeedDigest(path, testFeedTitle, "run-new", newPub, newPub, sampleRows()); err != nil {
+	if _, err := updateFeedDigest(path, testOpts, "run-new", newPub, newPub, sampleRows()); err != nil {
 		t.Fatal(err)
 	}
 
@@ -273,3 +273,63 @@ func TestUpdateFeedDigestTruncatesWhenAllParseable(t *testing.T) {
 		t.Fatalf("with all keys parseable the cap applies: got %d, want %d", len(feed.Entry), feedCap)
 	}
 }
+
+// A feed's own cap replaces feedCap, and noCap keeps every entry.
+func TestUpdateFeedDigestOwnCap(t *testing.T) {
+	dir := t.TempDir()
+	base := time.Date(2026, 8, 1, 0, 0, 0, 0, time.UTC)
+	for _, c := range []struct {
+		cap, runs, want int
+	}{
+		{3, 5, 3},
+		{noCap, feedCap + 5, feedCap + 5},
+	} {
+		path := filepath.Join(dir, fmt.Sprintf("feed-cap%d.xml", c.cap))
+		opts := feedOptions{Title: testFeedTitle, Cap: c.cap}
+		for i := 0; i < c.runs; i++ {
+			pub := base.Add(time.Duration(i) * time.Hour)
+			if _, err := updateFeedDigest(path, opts, fmt.Sprintf("run-%04d", i), pub, pub, sampleRows()); err != nil {
+				t.Fatal(err)
+			}
+		}
+		feed := parseFeed(t, path)
+		if len(feed.Entry) != c.want {
+			t.Errorf("cap %d: feed holds %d entries, want %d", c.cap, len(feed.Entry), c.want)
+		}
+		if pages, _ := archivePages(path); (len(pages) > 0) != (c.runs > c.want) {
+			t.Errorf("cap %d: archive pages %v", c.cap, pages)
+		}
+	}
+}
+
+// MaxAge moves out the per-game entries not updated in that long, to the archive, but
+// never one whose game is on this run's list however long it has gone unchanged.
+func TestUpdateFeedPerGameMaxAge(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "feed.xml")
+	opts := feedOptions{Title: testFeedTitle, MaxAge: 26 * 7 * 24 * time.Hour}
+	week := time.Date(2026, 1, 5, 9, 0, 0, 0, time.UTC)
+	for _, run := range []struct {
+		week  int
+		games []string
+	}{
+		{0, []string{"100", "200"}},
+		{1, []string{"100"}}, // 200 drops out and is marked
+		{40, []string{"100"}},
+	} {
+		if _, err := updateFeedPerGame(path, opts, week.AddDate(0, 0, 7*run.week), leftRows(run.games...)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	feed := parseFeed(t, path)
+	if got := entryIDs(feed); len(got) != 1 || got[0] != tagPrefix+gamePrefix+"100" {
+		t.Errorf("feed entries = %v, want only the listed game", got)
+	}
+	if e := findEntry(feed, tagPrefix+gamePrefix+"100"); e == nil || e.Updated != week.Format(time.RFC3339) {
+		t.Errorf("listed game's entry = %+v, want it kept unchanged since week 0", e)
+	}
+	page := parseFeed(t, archivePagePath(path, week.AddDate(0, 0, 7*40), 1))
+	if findEntry(page, tagPrefix+gamePrefix+"200") == nil {
+		t.Errorf("the stale entry is not on the archive page: %v", entryIDs(page))
+	}
+}
@@ -0,0 +1,58 @@
+package aggregate
+
+import (
+	"github.com/fzerorubigd/bggo"
+)
+
+// Categories. A per-game entry carries its game's BGG categories, mechanics, designers
+// and publishers as Atom <category> elements, the scheme naming the BGG listing a term
+// belongs to so a designer and a publisher of the same name stay apart.
+
+// Category schemes, one per kind of BGG link.
+const (
+	schemeCategory  = "https://boardgamegeek.com/boardgamecategory/"
+	schemeMechanic  = "https://boardgamegeek.com/boardgamemechanic/"
+	schemeDesigner  = "https://boardgamegeek.com/boardgamedesigner/"
+	schemePublisher = "https://boardgamegeek.com/boardgamepublisher/"
+)
+
+type atomCategory struct {
+	Term   string `xml:"term,attr"`
+	Scheme string `xml:"scheme,attr,omitempty"`
+}
+
+// thingCategories is t's categories, mechanics, designers and publishers, each kind in
+// BGG's order. A link without a name has no term to show and is skipped.
+func thingCategories(t bggo.ThingResult) []atomCategory {
+	var cats []atomCategory
+	for _, kind := range []struct {
+		scheme string
+		links  []bggo.Link
+	}{
+		{schemeCategory, t.Categories},
+		{schemeMechanic, t.Mechanics},
+		{schemeDesigner, t.Designers},
+		{schemePublisher, t.Publishers},
+	} {
+		for _, l := range kind.links {
+			if l.Name != "" {
+				cats = append(cats, atomCategory{Term: l.Name, Scheme: kind.scheme})
+			}
+		}
+	}
+	return cats
+}
+
+// sameCategories reports whether two category slices are element-wise equal, the change
+// detector for the per-game Category field.
+func sameCategories(a, b []atomCategory) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
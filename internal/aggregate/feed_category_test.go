@@ -0,0 +1,66 @@
+package aggregate
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/fzerorubigd/bggo"
+)
+
+// A per-game entry carries its game's categories, mechanics, designers and publishers
+// with one scheme per kind, in JSON as tags and in RSS with the scheme as domain, and
+// keeps them through a run whose lookup missed the game without that counting as a
+// change.
+func TestPerGameCategoriesStickyAcrossMiss(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "feed.xml")
+	opts := feedOptions{Title: testFeedTitle, RSS: true, Games: gamesFromThings(map[int64]bggo.ThingResult{
+		174430: {
+			ID:         174430,
+			Name:       "Gloomhaven",
+			Categories: []bggo.Link{{ID: 1022, Name: "Adventure"}},
+			Mechanics:  []bggo.Link{{ID: 2023, Name: "Cooperative Game"}, {ID: 2999, Name: ""}},
+			Designers:  []bggo.Link{{ID: 69802, Name: "Isaac Childres"}},
+			Publishers: []bggo.Link{{ID: 27425, Name: "Cephalofair Games"}},
+		},
+	})}
+	gen := time.Date(2026, 8, 11, 9, 0, 0, 0, time.UTC)
+	if _, err := updateFeedPerGame(path, opts, gen, gloomhavenRow("1")); err != nil {
+		t.Fatal(err)
+	}
+	want := []atomCategory{
+		{Term: "Adventure", Scheme: schemeCategory},
+		{Term: "Cooperative Game", Scheme: schemeMechanic},
+		{Term: "Isaac Childres", Scheme: schemeDesigner},
+		{Term: "Cephalofair Games", Scheme: schemePublisher},
+	}
+	if e := parseFeed(t, path).Entry[0]; !sameCategories(e.Category, want) {
+		t.Errorf("categories = %+v, want %+v", e.Category, want)
+	}
+	jf := readJSONFeed(t, filepath.Join(filepath.Dir(path), "feed.json"))
+	if got := strings.Join(jf.Items[0].Tags, "|"); got != "Adventure|Cooperative Game|Isaac Childres|Cephalofair Games" {
+		t.Errorf("json tags = %q", got)
+	}
+	rss, err := os.ReadFile(rssFeedPath(path))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `<category domain="` + schemeMechanic + `">Cooperative Game</category>`; !bytes.Contains(rss, []byte(want)) {
+		t.Errorf("rss lacks %s:\n%s", want, rss)
+	}
+
+	first, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	changed, err := updateFeedPerGame(path, testOpts, gen.AddDate(0, 0, 7), gloomhavenRow("1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if second, _ := os.ReadFile(path); len(changed) > 0 || !bytes.Equal(first, second) {
+		t.Errorf("a missed lookup must keep the categories and change nothing:\n%s\n%s", first, second)
+	}
+}
@@ -0,0 +1,407 @@
+package aggregate
+
+import (
+	"context"
+	"encoding/xml"
+	"errors"
+	"flag"
+	"fmt"
+	"maps"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/fzerorubigd/bgg-hotness/internal/config"
+)
+
+// Feed checks. The invariants the feed writers keep are spread over comments in this
+// package; feedcheck reads feed files back and checks them in one place, so a defect in
+// a writer, or a hand edit on the feed branch, is caught before it is pushed to
+// subscribers rather than by a reader's dedupe going wrong. It checks:
+//
+//   - what RFC 4287 requires of a feed and an entry: an absolute id, a title, an RFC 3339
+//     updated (and published, where present), an author, a link href and a category
+//     term, content or an alternate link, and at most one alternate link per type;
+//   - the entry ids: a per-game id is tagPrefix + "game:" + a BGG id, a digest id is
+//     tagPrefix + slug(title), no file mixes the two shapes, and no id appears twice in a
+//     file or across the subscription feeds checked together;
+//   - the feed ids: each subscription feed's id is the one its file name derives (see
+//     feedIDForPath), no two are the same, and an archive page carries the id of the feed
+//     its rel="current" link names;
+//   - links: a per-game entry has exactly one rel="alternate" link and otherwise only its
+//     thumbnail enclosure; a digest entry has none; an archive link names a file that
+//     exists next to it;
+//   - order and size: entries newest-first on the feed's sort key (updated per-game,
+//     published on a digest), the feed's updated the newest entry updated, and a
+//     subscription feed within its cap and holding no entry older than its max age, unless a
+//     sort key does not parse — the one case finalizeFeed skips both (capSafe), which is
+//     reported as the bad timestamp instead.
+//
+// Every feed sets its own cap and max age, and the feeds checked together are usually all
+// three, so feedcheck takes each file's limits from the feed in the config whose file has
+// the same base name, the ones the aggregate run that wrote it used. -cap overrides the
+// caps: -cap=N for every file, -cap=FILE=N for one. The age is measured back from the
+// feed's updated, the newest entry updated, which is no later than the run that wrote it: an
+// entry older than that by more than the max age was already too old on that run, so a
+// feed last written a week ago is not faulted for entries that have aged since.
+//
+// The file is read into the same structs the writers use, so feedcheck sees a feed the
+// way the next aggregate run will; an element those structs do not know, or a second copy
+// of a single-valued one, is not seen.
+
+// violation is one broken rule, in one file and, for an entry-level rule, one entry.
+type violation struct {
+	Path  string
+	Entry string // The entry id, "" for a feed-level rule.
+	Msg   string
+}
+
+func (v violation) String() string {
+	if v.Entry == "" {
+		return v.Path + ": " + v.Msg
+	}
+	return v.Path + ": entry " + v.Entry + ": " + v.Msg
+}
+
+// feedCaps is the cap of a subscription feed by file base name, and the cap of any other.
+// 0 means no cap, as it does for aggregate -cap.
+type feedCaps struct {
+	def    int
+	byFile map[string]int
+}
+
+func (c *feedCaps) String() string {
+	parts := []string{strconv.Itoa(c.def)}
+	for _, name := range slices.Sorted(maps.Keys(c.byFile)) {
+		parts = append(parts, name+"="+strconv.Itoa(c.byFile[name]))
+	}
+	return strings.Join(parts, ",")
+}
+
+// Set takes N, the cap of every feed, or FILE=N, the cap of the feed whose base name is
+// FILE.
+func (c *feedCaps) Set(v string) error {
+	name, n, named := strings.Cut(v, "=")
+	if !named {
+		name, n = "", v
+	}
+	limit, err := strconv.Atoi(n)
+	if err != nil || limit < 0 {
+		return fmt.Errorf("cap %q: want a count of 0 or more", n)
+	}
+	if !named {
+		c.def, c.byFile = limit, nil
+		return nil
+	}
+	if c.byFile == nil {
+		c.byFile = map[string]int{}
+	}
+	c.byFile[name] = limit
+	return nil
+}
+
+// of is the cap of the feed at path.
+func (c feedCaps) of(path string) int {
+	if limit, ok := c.byFile[filepath.Base(path)]; ok {
+		return limit
+	}
+	return c.def
+}
+
+// configuredLimits is the cap and max age of each feed in cfg, by file base name: every
+// named feed, then the one FEED_FILE names with the FEED_* overrides, as aggregate sees it.
+func configuredLimits(cfg config.Config) (feedCaps, map[string]time.Duration) {
+	caps := feedCaps{def: feedCap, byFile: map[string]int{}}
+	maxAges := map[string]time.Duration{}
+	var feeds []config.Feed
+	for _, name := range slices.Sorted(maps.Keys(cfg.Feeds)) {
+		feeds = append(feeds, cfg.Feeds[name])
+	}
+	feeds = append(feeds, cfg.Feed(""))
+	for _, f := range feeds {
+		if f.File == "" {
+			continue
+		}
+		name := filepath.Base(f.File)
+		if f.Cap != nil {
+			caps.byFile[name] = *f.Cap
+		}
+		if f.MaxAgeDays > 0 {
+			maxAges[name] = time.Duration(f.MaxAgeDays) * 24 * time.Hour
+		}
+	}
+	return caps, maxAges
+}
+
+// RunCheck checks the feed files named on the command line and prints each violation.
+// It fails when there are any, so the publish step stops before the push.
+func RunCheck(ctx context.Context, cfg config.Config, args []string) error {
+	fs := flag.NewFlagSet("feedcheck", flag.ContinueOnError)
+	caps, maxAges := configuredLimits(cfg)
+	fs.Var(&caps, "cap", "Most entries a subscription feed may hold, 0 for no limit, instead of its configured cap; FILE=N sets it for the feed with that file name only")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() == 0 {
+		return errors.New("no feed files to check")
+	}
+	vs, err := checkFeeds(fs.Args(), caps, maxAges)
+	if err != nil {
+		return err
+	}
+	for _, v := range vs {
+		fmt.Println(v)
+	}
+	if len(vs) > 0 {
+		return fmt.Errorf("%d feed violations", len(vs))
+	}
+	return nil
+}
+
+// checkFeeds checks each file on its own and the subscription feeds among them against
+// each other. maxAges is the max age of a feed by file base name. A file that does not
+// parse is a violation; one that cannot be read is an error.
+func checkFeeds(paths []string, caps feedCaps, maxAges map[string]time.Duration) ([]violation, error) {
+	var vs []violation
+	feedIDs := map[string]string{}  // Subscription feed id -> path.
+	entryIDs := map[string]string{} // Entry id in a subscription feed -> path.
+	for _, path := range paths {
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		var feed atomFeed
+		if err := xml.Unmarshal(b, &feed); err != nil {
+			vs = append(vs, violation{Path: path, Msg: "does not parse as an Atom feed: " + err.Error()})
+			continue
+		}
+		vs = append(vs, checkFeed(path, feed, caps.of(path), maxAges[filepath.Base(path)])...)
+		if feed.Archive != nil {
+			continue
+		}
+		if other, ok := feedIDs[feed.ID]; ok && feed.ID != "" {
+			vs = append(vs, violation{Path: path, Msg: fmt.Sprintf("feed id %q is also the id of %s", feed.ID, other)})
+		}
+		feedIDs[feed.ID] = path
+		seen := map[string]bool{}
+		for _, e := range feed.Entry {
+			if other, ok := entryIDs[e.ID]; ok && other != path && !seen[e.ID] {
+				vs = append(vs, violation{Path: path, Entry: e.ID, Msg: "id is also an entry of " + other})
+			}
+			seen[e.ID] = true
+			entryIDs[e.ID] = path
+		}
+	}
+	return vs, nil
+}
+
+// checkFeed checks one parsed feed file; limit and maxAge are the cap and max age a
+// subscription feed is held to, 0 for none.
+func checkFeed(path string, feed atomFeed, limit int, maxAge time.Duration) []violation {
+	var vs []violation
+	feedErr := func(format string, a ...any) {
+		vs = append(vs, violation{Path: path, Msg: fmt.Sprintf(format, a...)})
+	}
+	entryErr := func(e atomEntry, format string, a ...any) {
+		vs = append(vs, violation{Path: path, Entry: e.ID, Msg: fmt.Sprintf(format, a...)})
+	}
+	archive := feed.Archive != nil
+
+	if !absoluteIRI(feed.ID) {
+		feedErr("feed id %q is not an absolute IRI", feed.ID)
+	}
+	if feed.Title == "" {
+		feedErr("feed has no title")
+	}
+	if _, err := time.Parse(time.RFC3339, feed.Updated); err != nil {
+		feedErr("feed updated %q is not RFC 3339", feed.Updated)
+	}
+	if feed.Author.Name == "" {
+		// Entries here never carry their own author, so the feed must.
+		feedErr("feed has no author")
+	}
+	vs = append(vs, checkLinks(path, "", feed.Link)...)
+	current := ""
+	for _, l := range feed.Link {
+		switch l.Rel {
+		case relCurrent:
+			current = l.Href
+			fallthrough
+		case relPrevArchive, relNextArchive:
+			if _, err := os.Stat(filepath.Join(filepath.Dir(path), filepath.FromSlash(l.Href))); err != nil {
+				feedErr("%s link %q does not name a file next to the feed", l.Rel, l.Href)
+			}
+		}
+	}
+	switch {
+	case archive && current == "":
+		feedErr("archive page has no %s link", relCurrent)
+	case archive && feed.ID != feedIDForPath(current):
+		feedErr("archive page id %q is not the id of its feed %s, %q", feed.ID, current, feedIDForPath(current))
+	case !archive && feed.ID != feedIDForPath(path):
+		feedErr("feed id %q is not the id its file name derives, %q", feed.ID, feedIDForPath(path))
+	}
+
+	// The feed's shape is the shape of its entries; a feed holding both is a
+	// misconfiguration (see "Decision 2" in feed.go) and has no one sort key to check.
+	perGame, digest := 0, 0
+	for _, e := range feed.Entry {
+		if strings.HasPrefix(e.ID, tagPrefix+gamePrefix) {
+			perGame++
+		} else {
+			digest++
+		}
+	}
+	mixed := perGame > 0 && digest > 0
+	if mixed {
+		feedErr("feed mixes %d per-game and %d digest entries", perGame, digest)
+	}
+	sortByPublished := digest > perGame
+
+	seen := map[string]bool{}
+	var maxUpdated time.Time
+	capSafe := true
+	var prev time.Time
+	// listed is the games on the per-game feed's last run, which the age cap spares, and
+	// stale the entries it should not have.
+	listed := parseListed(feed.Listed)
+	var stale []atomEntry
+	var cutoff time.Time
+	if u, err := time.Parse(time.RFC3339, feed.Updated); err == nil && maxAge > 0 {
+		cutoff = u.Add(-maxAge)
+	}
+	for _, e := range feed.Entry {
+		if seen[e.ID] {
+			entryErr(e, "id appears more than once in the feed")
+		}
+		seen[e.ID] = true
+		if !absoluteIRI(e.ID) {
+			entryErr(e, "id is not an absolute IRI")
+		}
+		if e.Title == "" {
+			entryErr(e, "entry has no title")
+		}
+		updated, uerr := time.Parse(time.RFC3339, e.Updated)
+		if uerr != nil {
+			entryErr(e, "updated %q is not RFC 3339", e.Updated)
+		} else if updated.After(maxUpdated) {
+			maxUpdated = updated
+		}
+		published, perr := time.Parse(time.RFC3339, e.Published)
+		if e.Published != "" && perr != nil {
+			entryErr(e, "published %q is not RFC 3339", e.Published)
+		}
+		for _, c := range e.Category {
+			if c.Term == "" {
+				entryErr(e, "category has no term")
+			}
+		}
+		vs = append(vs, checkLinks(path, e.ID, e.Link)...)
+
+		alternate := 0
+		for _, l := range e.Link {
+			if l.Rel == "alternate" || l.Rel == "" {
+				alternate++
+			}
+		}
+		if e.Content.Text == "" && alternate == 0 {
+			entryErr(e, "entry has neither content nor an alternate link")
+		}
+		switch e.Content.Type {
+		case "", "text", "html", "xhtml":
+		default:
+			if !strings.Contains(e.Content.Type, "/") {
+				entryErr(e, "content type %q is neither text, html, xhtml nor a media type", e.Content.Type)
+			}
+		}
+
+		if id, ok := strings.CutPrefix(e.ID, tagPrefix+gamePrefix); ok {
+			if !isDigits(id) {
+				entryErr(e, "per-game id does not end in a BGG id")
+			}
+			if alternate != 1 {
+				entryErr(e, "per-game entry has %d alternate links, want 1", alternate)
+			}
+			for _, l := range e.Link {
+				if l.Rel != "alternate" && l.Rel != "" && l.Rel != "enclosure" {
+					entryErr(e, "per-game entry has a rel=%q link", l.Rel)
+				}
+			}
+		} else {
+			if rest, ok := strings.CutPrefix(e.ID, tagPrefix); !ok || rest == "" || slug(rest) != rest {
+				entryErr(e, "id is neither a per-game nor a digest id")
+			}
+			if len(e.Link) > 0 {
+				entryErr(e, "digest entry has %d links, want none", len(e.Link))
+			}
+		}
+
+		key, kerr := updated, uerr
+		if sortByPublished {
+			key, kerr = published, perr
+		}
+		if kerr != nil {
+			capSafe = false
+			continue
+		}
+		if !mixed && !prev.IsZero() && key.After(prev) {
+			entryErr(e, "entry is newer than the one before it")
+		}
+		prev = key
+		if _, ok := listed[e.ID]; !ok && !cutoff.IsZero() && key.Before(cutoff) {
+			stale = append(stale, e)
+		}
+	}
+	if !maxUpdated.IsZero() && feed.Updated != maxUpdated.UTC().Format(time.RFC3339) {
+		feedErr("feed updated %q is not the newest entry updated, %q", feed.Updated, maxUpdated.UTC().Format(time.RFC3339))
+	}
+	if !archive && capSafe && limit > 0 && len(feed.Entry) > limit {
+		feedErr("feed holds %d entries, over the cap of %d", len(feed.Entry), limit)
+	}
+	if !archive && capSafe {
+		for _, e := range stale {
+			entryErr(e, "entry is older than the feed's max age of %d days", int(maxAge.Hours()/24))
+		}
+	}
+	return vs
+}
+
+// checkLinks checks what RFC 4287 requires of a set of links: each has an href, and no
+// two alternate links share a type. entry is "" for feed-level links.
+func checkLinks(path, entry string, links []atomLink) []violation {
+	var vs []violation
+	alternates := map[string]bool{}
+	for _, l := range links {
+		if l.Href == "" {
+			vs = append(vs, violation{Path: path, Entry: entry, Msg: fmt.Sprintf("rel=%q link has no href", l.Rel)})
+		}
+		if l.Rel == "alternate" || l.Rel == "" {
+			if alternates[l.Type] {
+				vs = append(vs, violation{Path: path, Entry: entry, Msg: fmt.Sprintf("more than one alternate link of type %q", l.Type)})
+			}
+			alternates[l.Type] = true
+		}
+	}
+	return vs
+}
+
+// absoluteIRI reports whether s parses as a URI with a scheme, which every tag: and
+// https: id this package writes does.
+func absoluteIRI(s string) bool {
+	u, err := url.Parse(s)
+	return err == nil && u.Scheme != ""
+}
+
+// isDigits reports whether s is a non-empty run of ASCII digits.
+func isDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return s != ""
+}
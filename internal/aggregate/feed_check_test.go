@@ -0,0 +1,235 @@
+package aggregate
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/fzerorubigd/bggo"
+
+	"github.com/fzerorubigd/bgg-hotness/internal/config"
+)
+
+func violationStrings(vs []violation) []string {
+	s := make([]string, len(vs))
+	for i, v := range vs {
+		s[i] = v.String()
+	}
+	return s
+}
+
+// The three feeds as the writers leave them, archive pages included, pass.
+func TestCheckFeedsPassesWrittenFeeds(t *testing.T) {
+	dir := t.TempDir()
+	weekly := filepath.Join(dir, "feed.xml")
+	opts := feedOptions{Title: testFeedTitle, History: true, BaseURL: "https://example.com/feed", Games: gamesFromThings(map[int64]bggo.ThingResult{
+		174430: {ID: 174430, Name: "Gloomhaven", Thumbnail: gloomhavenThumb},
+	})}
+	week := time.Date(2026, 8, 4, 9, 0, 0, 0, time.UTC)
+	for i := range 3 {
+		if _, err := updateFeedPerGame(weekly, opts, week.AddDate(0, 0, 7*i), perGameRows()[i%2:]); err != nil {
+			t.Fatal(err)
+		}
+	}
+	monthly := filepath.Join(dir, "feed-monthly.xml")
+	for i := range feedCap + 5 {
+		when := week.Add(time.Duration(i) * time.Hour)
+		if _, err := updateFeedDigest(monthly, testOpts, fmt.Sprintf("month-%04d", i), when, when, sampleRows()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	yearly := filepath.Join(dir, "feed-yearly.xml")
+	if _, err := updateFeedDigest(yearly, testOpts, "2026", week, week, sampleRows()); err != nil {
+		t.Fatal(err)
+	}
+
+	paths, err := filepath.Glob(filepath.Join(dir, "*.xml"))
+	if err != nil || len(paths) != 3+5 {
+		t.Fatalf("feed files = %v, %v; want the three feeds and five archive pages", paths, err)
+	}
+	vs, err := checkFeeds(paths, feedCaps{def: feedCap}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(vs) > 0 {
+		t.Errorf("written feeds have violations:\n%s", strings.Join(violationStrings(vs), "\n"))
+	}
+}
+
+// Each broken rule is reported against the entry that breaks it.
+func TestCheckFeedReportsEntryViolations(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "feed.xml")
+	game := func(id, updated string) atomEntry {
+		return atomEntry{
+			Title: "Game " + id, ID: tagPrefix + gamePrefix + id,
+			Published: "2026-01-01T00:00:00Z", Updated: updated,
+			Link:    []atomLink{{Rel: "alternate", Href: "https://boardgamegeek.com/boardgame/" + id}},
+			Content: atomContent{Type: "html", Text: "<p>x</p>"},
+		}
+	}
+	twoAlternates := game("abc", "2026-01-02T00:00:00Z")
+	twoAlternates.Link = append(twoAlternates.Link, atomLink{Rel: "alternate", Href: "https://example.com/"})
+	writeRawFeed(t, path, []atomEntry{
+		game("1", "2026-01-03T00:00:00Z"),
+		game("2", "yesterday"),
+		game("3", "2026-01-04T00:00:00Z"),
+		twoAlternates,
+		game("1", "2026-01-01T00:00:00Z"),
+	})
+
+	vs, err := checkFeeds([]string{path}, feedCaps{def: feedCap}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := violationStrings(vs)
+	for _, want := range []string{
+		path + `: feed updated "" is not RFC 3339`,
+		path + `: feed updated "" is not the newest entry updated, "2026-01-04T00:00:00Z"`,
+		path + `: entry ` + tagPrefix + gamePrefix + `2: updated "yesterday" is not RFC 3339`,
+		path + `: entry ` + tagPrefix + gamePrefix + `3: entry is newer than the one before it`,
+		path + `: entry ` + tagPrefix + gamePrefix + `abc: per-game id does not end in a BGG id`,
+		path + `: entry ` + tagPrefix + gamePrefix + `abc: per-game entry has 2 alternate links, want 1`,
+		path + `: entry ` + tagPrefix + gamePrefix + `abc: more than one alternate link of type ""`,
+		path + `: entry ` + tagPrefix + gamePrefix + `1: id appears more than once in the feed`,
+	} {
+		if !slices.Contains(got, want) {
+			t.Errorf("violations lack %q; got:\n%s", want, strings.Join(got, "\n"))
+		}
+	}
+	if len(got) != 8 {
+		t.Errorf("got %d violations, want 8:\n%s", len(got), strings.Join(got, "\n"))
+	}
+}
+
+// A feed over the cap is a violation only when every sort key parses; otherwise the cap
+// was rightly skipped (capSafe) and only the bad timestamp is reported.
+func TestCheckFeedCapFollowsCapSafe(t *testing.T) {
+	entries := rawPerGameEntries()
+	slices.Reverse(entries)
+	feed := atomFeed{Title: testFeedTitle, ID: feedIDForPath("feed.xml"), Updated: entries[0].Updated, Author: atomAuthor{Name: authorName}, Entry: entries}
+	want := []string{fmt.Sprintf("feed.xml: feed holds %d entries, over the cap of %d", feedCap+1, feedCap)}
+	if got := violationStrings(checkFeed("feed.xml", feed, feedCap, 0)); !slices.Equal(got, want) {
+		t.Errorf("over the cap: %q, want %q", got, want)
+	}
+
+	feed.Entry[feedCap/2].Updated = "not-a-date"
+	want = []string{"feed.xml: entry " + feed.Entry[feedCap/2].ID + `: updated "not-a-date" is not RFC 3339`}
+	if got := violationStrings(checkFeed("feed.xml", feed, feedCap, 0)); !slices.Equal(got, want) {
+		t.Errorf("over the cap with a bad key: %q, want %q", got, want)
+	}
+}
+
+// A feed file copied under another name keeps an id its name does not derive, and shares
+// its entries with the original.
+func TestCheckFeedsAcrossFiles(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "feed.xml")
+	if _, err := updateFeedPerGame(path, testOpts, time.Date(2026, 8, 4, 9, 0, 0, 0, time.UTC), gloomhavenRow("1")); err != nil {
+		t.Fatal(err)
+	}
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	copied := filepath.Join(dir, "feed-weekly.xml")
+	if err := os.WriteFile(copied, b, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	vs, err := checkFeeds([]string{path, copied}, feedCaps{def: feedCap}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		copied + `: feed id "` + tagPrefix + `feed" is not the id its file name derives, "` + tagPrefix + `feed-weekly"`,
+		copied + `: feed id "` + tagPrefix + `feed" is also the id of ` + path,
+		copied + `: entry ` + tagPrefix + gamePrefix + `174430: id is also an entry of ` + path,
+	}
+	if got := violationStrings(vs); !slices.Equal(got, want) {
+		t.Errorf("violations = %q, want %q", got, want)
+	}
+}
+
+// -cap takes a default and per-file caps, and refuses a cap that is not a count.
+func TestFeedCapsSet(t *testing.T) {
+	caps := feedCaps{def: feedCap}
+	for _, v := range []string{"100", "feed-yearly.xml=0"} {
+		if err := caps.Set(v); err != nil {
+			t.Fatalf("Set(%q): %v", v, err)
+		}
+	}
+	if got := caps.of("/feed/feed.xml"); got != 100 {
+		t.Errorf("cap of feed.xml = %d, want the default 100", got)
+	}
+	if got := caps.of("/feed/feed-yearly.xml"); got != 0 {
+		t.Errorf("cap of feed-yearly.xml = %d, want 0", got)
+	}
+	if got := caps.String(); got != "100,feed-yearly.xml=0" {
+		t.Errorf("String() = %q", got)
+	}
+	for _, v := range []string{"feed.xml=-1", "many"} {
+		if err := caps.Set(v); err == nil {
+			t.Errorf("Set(%q) should fail", v)
+		}
+	}
+}
+
+// An entry older than the max age, measured back from the feed's updated, is a violation
+// unless its game was on the last run; a bad sort key skips the age cap as it does the cap.
+func TestCheckFeedMaxAgeSparesListed(t *testing.T) {
+	entries := rawPerGameEntries()[:4]
+	slices.Reverse(entries)
+	feed := atomFeed{
+		Title: testFeedTitle, ID: feedIDForPath("feed.xml"), Updated: entries[0].Updated,
+		Author: atomAuthor{Name: authorName}, Entry: entries,
+		Listed: "1000:2026-01-01:1",
+	}
+	// Entries are an hour apart: 1003 is the newest, 1001 and 1000 are over two hours old.
+	want := []string{"feed.xml: entry " + tagPrefix + gamePrefix + "1001: entry is older than the feed's max age of 0 days"}
+	if got := violationStrings(checkFeed("feed.xml", feed, feedCap, 90*time.Minute)); !slices.Equal(got, want) {
+		t.Errorf("violations = %q, want %q", got, want)
+	}
+	if got := violationStrings(checkFeed("feed.xml", feed, feedCap, 0)); len(got) > 0 {
+		t.Errorf("with no max age: %q, want none", got)
+	}
+
+	feed.Entry[1].Updated = "not-a-date"
+	want = []string{"feed.xml: entry " + feed.Entry[1].ID + `: updated "not-a-date" is not RFC 3339`}
+	if got := violationStrings(checkFeed("feed.xml", feed, feedCap, 90*time.Minute)); !slices.Equal(got, want) {
+		t.Errorf("with a bad key: %q, want %q", got, want)
+	}
+}
+
+// Each file is held to the cap and max age of the configured feed with its base name, and
+// -cap overrides the configured caps.
+func TestConfiguredLimits(t *testing.T) {
+	zero := 0
+	cfg := config.Config{Feeds: map[string]config.Feed{
+		"weekly": {File: "feed.xml", MaxAgeDays: 182},
+		"yearly": {File: "out/feed-yearly.xml", Cap: &zero},
+	}}
+	caps, maxAges := configuredLimits(cfg)
+	if got := caps.of("/site/feed-yearly.xml"); got != 0 {
+		t.Errorf("cap of feed-yearly.xml = %d, want the configured 0", got)
+	}
+	if got := caps.of("/site/feed.xml"); got != feedCap {
+		t.Errorf("cap of feed.xml = %d, want the default %d", got, feedCap)
+	}
+	if got, want := maxAges["feed.xml"], 182*24*time.Hour; got != want {
+		t.Errorf("max age of feed.xml = %v, want %v", got, want)
+	}
+	if _, ok := maxAges["feed-yearly.xml"]; ok {
+		t.Error("feed-yearly.xml has a max age, want none")
+	}
+
+	if err := caps.Set("50"); err != nil {
+		t.Fatal(err)
+	}
+	if got := caps.of("/site/feed-yearly.xml"); got != 50 {
+		t.Errorf("cap of feed-yearly.xml after -cap=50 = %d, want 50", got)
+	}
+}
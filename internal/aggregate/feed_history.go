@@ -0,0 +1,162 @@
+package aggregate
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/fzerorubigd/bgg-hotness/internal/history"
+)
+
+// Per-game history. With feedOptions.History on, a per-game entry adds its weeks on the
+// list, previous rank, best rank, first-seen date and a sparkline of its recent daily
+// ranks; the weekly ranks are kept in the entry's <ranks> element, so the feed file is
+// its own state.
+
+const (
+	// sparkDays is how many recent daily lists the sparkline covers, and sparkWeeks how
+	// many weekly ranks it falls back to.
+	sparkDays  = 28
+	sparkWeeks = 12
+	// sparkScale is the rank drawn as the lowest bar: the length of the BGG hot list.
+	sparkScale = 50
+)
+
+// sparkBars are the sparkline's levels, lowest first; sparkOff marks a day off the list.
+const (
+	sparkBars = "▁▂▃▄▅▆▇█"
+	sparkOff  = "·"
+)
+
+// rankRecord is a game's rank in one run.
+type rankRecord struct {
+	Date string // YYYY-MM-DD, UTC.
+	Rank int
+}
+
+// parseRanks reads an entry's <ranks> state. A pair that does not parse is dropped, so a
+// hand-edited entry loses that run rather than failing the feed.
+func parseRanks(s string) []rankRecord {
+	var recs []rankRecord
+	for _, f := range strings.Fields(s) {
+		date, rank, ok := strings.Cut(f, ":")
+		if !ok {
+			continue
+		}
+		n, err := strconv.Atoi(rank)
+		if _, derr := time.Parse(time.DateOnly, date); err != nil || derr != nil || n < 1 {
+			continue
+		}
+		recs = append(recs, rankRecord{Date: date, Rank: n})
+	}
+	return recs
+}
+
+func formatRanks(recs []rankRecord) string {
+	parts := make([]string, len(recs))
+	for i, r := range recs {
+		parts[i] = r.Date + ":" + strconv.Itoa(r.Rank)
+	}
+	return strings.Join(parts, " ")
+}
+
+// recordRank sets date's rank in recs, which stay in date order.
+func recordRank(recs []rankRecord, date string, rank int) []rankRecord {
+	for i := range recs {
+		switch {
+		case recs[i].Date == date:
+			recs[i].Rank = rank
+			return recs
+		case recs[i].Date > date:
+			return append(recs[:i], append([]rankRecord{{Date: date, Rank: rank}}, recs[i:]...)...)
+		}
+	}
+	return append(recs, rankRecord{Date: date, Rank: rank})
+}
+
+// dailyRanks is id's rank on each of days, 0 on a day it was off the list.
+func dailyRanks(days []history.Day, id string) []int {
+	n, err := strconv.ParseInt(id, 10, 64)
+	if err != nil || len(days) == 0 {
+		return nil
+	}
+	points := history.Game(days, n)
+	ranks := make([]int, len(points))
+	for i, p := range points {
+		ranks[i] = p.Rank
+	}
+	return ranks
+}
+
+// renderGameHistory is the history part of a per-game entry body, appended to
+// renderGameContent. recs are the game's weekly ranks with this run's last, since the
+// date its current stretch on the list began (empty when the feed cannot tell, which
+// counts every rank as one stretch), published the entry's first-seen instant and daily
+// its recent daily ranks (nil without a store). Like renderGameContent it contains
+// nothing run-specific beyond the history itself.
+func renderGameHistory(recs []rankRecord, since, published string, daily []int) string {
+	if len(recs) == 0 {
+		return ""
+	}
+	// stretch is the ranks of the current stretch: a game back on the list starts its
+	// week count and previous rank over rather than carrying them from the last one.
+	stretch := recs
+	for i, r := range recs {
+		if r.Date >= since {
+			stretch = recs[i:]
+			break
+		}
+	}
+	var b strings.Builder
+	weeks := "weeks"
+	if len(stretch) == 1 {
+		weeks = "week"
+	}
+	fmt.Fprintf(&b, "<p>%d %s on the list", len(stretch), weeks)
+	switch {
+	case len(stretch) > 1:
+		fmt.Fprintf(&b, " · previous rank %d", stretch[len(stretch)-2].Rank)
+	case len(recs) > 1:
+		b.WriteString(" · back on the list")
+	default:
+		b.WriteString(" · new this week")
+	}
+	best := recs[0].Rank
+	for _, r := range recs[1:] {
+		best = min(best, r.Rank)
+	}
+	fmt.Fprintf(&b, " · best rank %d", best)
+	if t, err := time.Parse(time.RFC3339, published); err == nil {
+		fmt.Fprintf(&b, " · first seen %s", t.UTC().Format(time.DateOnly))
+	}
+	b.WriteString("</p>")
+
+	if len(daily) > 0 {
+		fmt.Fprintf(&b, "<p>Daily rank, last %d days: %s</p>", len(daily), sparkline(daily))
+	} else {
+		weekly := recs[max(0, len(recs)-sparkWeeks):]
+		ranks := make([]int, len(weekly))
+		for i, r := range weekly {
+			ranks[i] = r.Rank
+		}
+		fmt.Fprintf(&b, "<p>Weekly rank: %s</p>", sparkline(ranks))
+	}
+	return b.String()
+}
+
+// sparkline draws ranks as bars, rank 1 the tallest and sparkScale or worse the lowest,
+// with sparkOff for a 0 (off the list).
+func sparkline(ranks []int) string {
+	bars := []rune(sparkBars)
+	var b strings.Builder
+	for _, r := range ranks {
+		if r <= 0 {
+			b.WriteString(sparkOff)
+			continue
+		}
+		r = min(r, sparkScale)
+		b.WriteRune(bars[(sparkScale-r)*(len(bars)-1)/(sparkScale-1)])
+	}
+	return b.String()
+}
@@ -0,0 +1,120 @@
+package aggregate
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/fzerorubigd/bgg-hotness/internal/history"
+)
+
+func gloomhavenRow(rank string) [][]string {
+	return [][]string{{rank, "174430", "12", "https://boardgamegeek.com/boardgame/174430/", "Gloomhaven"}}
+}
+
+// Three weekly runs build up the story in the entry: the ranks are kept as entry state,
+// the content shows weeks, previous, best and first seen, and without daily lists the
+// sparkline draws the weekly ranks. A re-run on the same date changes nothing.
+func TestPerGameHistoryAcrossRuns(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "feed.xml")
+	opts := feedOptions{Title: testFeedTitle, History: true}
+	week := time.Date(2026, 8, 4, 9, 0, 0, 0, time.UTC)
+	for i, rank := range []string{"5", "3", "50"} {
+		if _, err := updateFeedPerGame(path, opts, week.AddDate(0, 0, 7*i), gloomhavenRow(rank)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	e := parseFeed(t, path).Entry[0]
+	if want := "2026-08-04:5 2026-08-11:3 2026-08-18:50"; e.Ranks != want {
+		t.Errorf("ranks = %q, want %q", e.Ranks, want)
+	}
+	for _, want := range []string{
+		"Rank 50 in the latest",
+		"3 weeks on the list · previous rank 3 · best rank 3 · first seen 2026-08-04",
+		"Weekly rank: ▇▇▁",
+	} {
+		if !strings.Contains(e.Content.Text, want) {
+			t.Errorf("content %q lacks %q", e.Content.Text, want)
+		}
+	}
+	if e.Updated != "2026-08-18T09:00:00Z" {
+		t.Errorf("updated = %q, want the last run", e.Updated)
+	}
+
+	first, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := updateFeedPerGame(path, opts, week.AddDate(0, 0, 14).Add(3*time.Hour), gloomhavenRow("50")); err != nil {
+		t.Fatal(err)
+	}
+	if second, _ := os.ReadFile(path); !bytes.Equal(first, second) {
+		t.Errorf("a re-run on the same date must be byte-identical:\n%s\n%s", first, second)
+	}
+}
+
+// With daily lists the sparkline draws the game's daily ranks, a dot for a day off the
+// list; a new game says so instead of giving a previous rank.
+func TestPerGameHistoryDailySparkline(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "feed.xml")
+	day := time.Date(2026, 8, 1, 0, 0, 0, 0, time.UTC)
+	var recent []history.Day
+	for i, rank := range []int{1, 0, 25, 50} {
+		d := history.Day{Date: day.AddDate(0, 0, i)}
+		if rank > 0 {
+			d.Entries = []history.Entry{{Rank: rank, ID: 174430}}
+		}
+		recent = append(recent, d)
+	}
+	opts := feedOptions{Title: testFeedTitle, History: true, Recent: recent}
+	if _, err := updateFeedPerGame(path, opts, day.AddDate(0, 0, 4), gloomhavenRow("2")); err != nil {
+		t.Fatal(err)
+	}
+	text := parseFeed(t, path).Entry[0].Content.Text
+	for _, want := range []string{"1 week on the list · new this week · best rank 2", "Daily rank, last 4 days: █·▄▁"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("content %q lacks %q", text, want)
+		}
+	}
+}
+
+// Weeks on the list and the previous rank are the current stretch's: a game that left and
+// came back starts them over, while its best rank and first seen keep the whole history.
+func TestPerGameHistoryCountsCurrentStretch(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "feed.xml")
+	opts := feedOptions{Title: testFeedTitle, History: true}
+	week := time.Date(2026, 8, 4, 9, 0, 0, 0, time.UTC)
+	run := func(i int, rows [][]string) string {
+		t.Helper()
+		if _, err := updateFeedPerGame(path, opts, week.AddDate(0, 0, 7*i), rows); err != nil {
+			t.Fatal(err)
+		}
+		return findEntry(parseFeed(t, path), tagPrefix+gamePrefix+"174430").Content.Text
+	}
+	run(0, gloomhavenRow("5"))
+	run(1, gloomhavenRow("3"))
+	run(2, leftRows("100"))
+
+	for i, want := range []string{
+		"1 week on the list · back on the list · best rank 3 · first seen 2026-08-04",
+		"2 weeks on the list · previous rank 7 · best rank 3 · first seen 2026-08-04",
+	} {
+		rank := []string{"7", "4"}[i]
+		if text := run(3+i, gloomhavenRow(rank)); !strings.Contains(text, want) {
+			t.Errorf("run %d: content %q lacks %q", 3+i, text, want)
+		}
+	}
+}
+
+func TestRecordRankKeepsDateOrder(t *testing.T) {
+	recs := parseRanks("2026-08-04:5 bogus 2026-08-18:4 2026-08-25:zero")
+	recs = recordRank(recs, "2026-08-11", 3)
+	recs = recordRank(recs, "2026-08-18", 2)
+	if got, want := formatRanks(recs), "2026-08-04:5 2026-08-11:3 2026-08-18:2"; got != want {
+		t.Errorf("ranks = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,102 @@
+package aggregate
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// JSON Feed output. Every Atom feed is also written as a JSON Feed 1.1 document
+// (https://jsonfeed.org/version/1.1) next to it, feed.xml -> feed.json, for consumers
+// that handle JSON far more easily than XML (the Discord bot, a Shortcuts automation).
+// It is a rendering of the FINALIZED atomFeed, not a second feed with its own state:
+// nothing is ever read back from the .json, so the Atom file stays the single source of
+// truth and the two cannot drift. In particular an item's id is the Atom entry's tag:
+// id verbatim, so a consumer that switches format keeps its dedupe.
+//
+// The mapping is one to one: title -> title, id -> id, published -> date_published,
+// updated -> date_modified, the rel="alternate" link -> url and the rel="enclosure"
+// thumbnail -> image and the category terms -> tags (per-game entries only, as in
+// Atom), and the html content -> content_html. Being derived from the byte-stable
+// Atom feed, an unchanged run writes a byte-identical .json too. At feed level the
+// rel="alternate" link is home_page_url, and feed_url is the rel="self" URL with the
+// .json name.
+
+const jsonFeedVersion = "https://jsonfeed.org/version/1.1"
+
+type jsonFeed struct {
+	Version     string       `json:"version"`
+	Title       string       `json:"title"`
+	HomePageURL string       `json:"home_page_url,omitempty"`
+	FeedURL     string       `json:"feed_url,omitempty"`
+	Authors     []jsonAuthor `json:"authors,omitempty"`
+	Items       []jsonItem   `json:"items"`
+}
+
+type jsonAuthor struct {
+	Name string `json:"name"`
+}
+
+type jsonItem struct {
+	ID            string   `json:"id"`
+	URL           string   `json:"url,omitempty"`
+	Title         string   `json:"title"`
+	Image         string   `json:"image,omitempty"`
+	Tags          []string `json:"tags,omitempty"`
+	ContentHTML   string   `json:"content_html"`
+	DatePublished string   `json:"date_published,omitempty"`
+	DateModified  string   `json:"date_modified,omitempty"`
+}
+
+// jsonFeedPath is the JSON Feed file for the Atom feed at path: the same name with a
+// .json extension.
+func jsonFeedPath(path string) string {
+	return strings.TrimSuffix(path, filepath.Ext(path)) + ".json"
+}
+
+func toJSONFeed(feed atomFeed) jsonFeed {
+	jf := jsonFeed{Version: jsonFeedVersion, Title: feed.Title, Items: []jsonItem{}}
+	if feed.Author.Name != "" {
+		jf.Authors = []jsonAuthor{{Name: feed.Author.Name}}
+	}
+	for _, l := range feed.Link {
+		switch l.Rel {
+		case "alternate":
+			jf.HomePageURL = l.Href
+		case "self":
+			jf.FeedURL = jsonFeedPath(l.Href)
+		}
+	}
+	for _, e := range feed.Entry {
+		item := jsonItem{
+			ID:            e.ID,
+			Title:         e.Title,
+			ContentHTML:   e.Content.Text,
+			DatePublished: e.Published,
+			DateModified:  e.Updated,
+		}
+		for _, l := range e.Link {
+			if l.Rel == "alternate" || l.Rel == "" {
+				item.URL = l.Href
+				break
+			}
+		}
+		item.Image = enclosure(e.Link)
+		for _, c := range e.Category {
+			item.Tags = append(item.Tags, c.Term)
+		}
+		jf.Items = append(jf.Items, item)
+	}
+	return jf
+}
+
+// saveJSONFeed writes feed as a JSON Feed to path when it changed, atomically like the
+// Atom file, and reports whether it did.
+func saveJSONFeed(path string, feed atomFeed) (bool, error) {
+	out, err := json.MarshalIndent(toJSONFeed(feed), "", "  ")
+	if err != nil {
+		return false, fmt.Errorf("marshal json feed: %w", err)
+	}
+	return writeIfChanged(path, append(out, '\n'))
+}
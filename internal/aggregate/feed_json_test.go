@@ -0,0 +1,95 @@
+package aggregate
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func readJSONFeed(t *testing.T, path string) jsonFeed {
+	t.Helper()
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read json feed: %v", err)
+	}
+	var jf jsonFeed
+	if err := json.Unmarshal(b, &jf); err != nil {
+		t.Fatalf("parse json feed: %v", err)
+	}
+	return jf
+}
+
+// The JSON Feed lists the same entries as the Atom feed, in the same order, with the
+// Atom ids verbatim, and a per-game item's url is the entry's BGG link.
+func TestJSONFeedMirrorsAtomPerGame(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "feed.xml")
+	if _, err := updateFeedPerGame(path, testOpts, time.Date(2026, 8, 11, 9, 0, 0, 0, time.UTC), perGameRows()); err != nil {
+		t.Fatalf("updateFeedPerGame: %v", err)
+	}
+	atom := parseFeed(t, path)
+	jf := readJSONFeed(t, filepath.Join(filepath.Dir(path), "feed.json"))
+
+	if jf.Version != "https://jsonfeed.org/version/1.1" || jf.Title != testFeedTitle {
+		t.Errorf("feed header = %q %q", jf.Version, jf.Title)
+	}
+	if len(jf.Items) != len(atom.Entry) {
+		t.Fatalf("json feed has %d items, atom %d entries", len(jf.Items), len(atom.Entry))
+	}
+	for i, e := range atom.Entry {
+		it := jf.Items[i]
+		if it.ID != e.ID || it.Title != e.Title || it.ContentHTML != e.Content.Text ||
+			it.DatePublished != e.Published || it.DateModified != e.Updated || it.URL != e.Link[0].Href {
+			t.Errorf("item %d = %+v, does not mirror entry %+v", i, it, e)
+		}
+	}
+}
+
+// A digest entry has no link in Atom and no url in JSON; an unchanged digest re-run
+// leaves the .json byte-identical, like the .xml.
+func TestJSONFeedDigestNoURLAndStable(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "feed-monthly.xml")
+	jsonPath := filepath.Join(filepath.Dir(path), "feed-monthly.json")
+	pub := time.Date(2026, 7, 31, 23, 59, 59, 0, time.UTC)
+	gen := time.Date(2026, 8, 1, 14, 0, 0, 0, time.UTC)
+	if _, err := updateFeedDigest(path, testOpts, "Monthly - 2026-7", pub, gen, sampleRows()); err != nil {
+		t.Fatalf("updateFeedDigest: %v", err)
+	}
+	first, err := os.ReadFile(jsonPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if jf := readJSONFeed(t, jsonPath); len(jf.Items) != 1 || jf.Items[0].URL != "" || jf.Items[0].ID != tagPrefix+"monthly-2026-7" {
+		t.Errorf("digest items = %+v", jf.Items)
+	}
+	if _, err := updateFeedDigest(path, testOpts, "Monthly - 2026-7", pub, gen, sampleRows()); err != nil {
+		t.Fatalf("re-run: %v", err)
+	}
+	second, err := os.ReadFile(jsonPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(first, second) {
+		t.Errorf("an unchanged re-run must leave the json feed byte-identical:\n%s\n%s", first, second)
+	}
+	if _, err := os.Stat(jsonPath + ".tmp"); !os.IsNotExist(err) {
+		t.Errorf("temp file left behind: %v", err)
+	}
+}
+
+// The feed-level links carry over: alternate is home_page_url and feed_url is the .json
+// next to the Atom feed's self URL.
+func TestJSONFeedLinks(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "feed-monthly.xml")
+	opts := feedOptions{Title: testFeedTitle, BaseURL: "https://example.org/bgg", SiteURL: "https://example.org/site/"}
+	pub := time.Date(2026, 7, 31, 23, 59, 59, 0, time.UTC)
+	if _, err := updateFeedDigest(path, opts, "Monthly - 2026-7", pub, pub, sampleRows()); err != nil {
+		t.Fatal(err)
+	}
+	jf := readJSONFeed(t, filepath.Join(filepath.Dir(path), "feed-monthly.json"))
+	if jf.HomePageURL != "https://example.org/site/" || jf.FeedURL != "https://example.org/bgg/feed-monthly.json" {
+		t.Errorf("home_page_url = %q, feed_url = %q", jf.HomePageURL, jf.FeedURL)
+	}
+}
@@ -0,0 +1,108 @@
+package aggregate
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Games leaving the list. A game that drops out of the weekly ranking gets one last
+// update to its per-game entry — "dropped out after N weeks on the list, peak rank X",
+// with updated bumped so readers see it — and is then left frozen: later runs do not
+// touch it again until the game re-enters, when the entry goes back to the normal
+// update-in-place path.
+//
+// "Dropped out" needs to know which games were on the list LAST run, which the entries
+// alone cannot say: an entry that has not changed in weeks may be a steady game or one
+// long gone. So the feed keeps that as state, one feed-level extension element listing
+// each game of the last run with the date its current stretch on the list began and its
+// best rank in that stretch:
+//
+//	<listed xmlns="https://github.com/fzerorubigd/bgg-hotness/ns/history">174430:2026-08-04:2 ...</listed>
+//
+// A game's stretch start and peak only move when its entry changes anyway (it enters, or
+// climbs to a new best), so the element never changes on a run that changes nothing
+// else, and an unchanged re-run stays byte-identical. A feed written before this existed
+// has no element, so its first run marks nothing — it cannot tell who left — and only
+// records the list; marking starts the run after.
+//
+// N is the weeks from the start of the stretch to the run that noticed the game gone,
+// which for the weekly job is the number of runs the game was on the list.
+
+// listing is a game's current stretch on the list.
+type listing struct {
+	Since string // YYYY-MM-DD, UTC: the run the stretch began.
+	Peak  int    // Best rank in the stretch, 0 when no rank parsed.
+}
+
+// parseListed reads the feed's <listed> state, keyed on entry id. A part that does not
+// parse is dropped.
+func parseListed(s string) map[string]listing {
+	listed := map[string]listing{}
+	for _, f := range strings.Fields(s) {
+		parts := strings.Split(f, ":")
+		if len(parts) != 3 {
+			continue
+		}
+		if _, err := time.Parse(time.DateOnly, parts[1]); err != nil {
+			continue
+		}
+		peak, err := strconv.Atoi(parts[2])
+		if err != nil {
+			continue
+		}
+		listed[tagPrefix+gamePrefix+parts[0]] = listing{Since: parts[1], Peak: peak}
+	}
+	return listed
+}
+
+// formatListed writes listed in game id order, so the same set always writes the same
+// bytes.
+func formatListed(listed map[string]listing) string {
+	ids := make([]string, 0, len(listed))
+	for id := range listed {
+		ids = append(ids, strings.TrimPrefix(id, tagPrefix+gamePrefix))
+	}
+	sort.Strings(ids)
+	parts := make([]string, len(ids))
+	for i, id := range ids {
+		l := listed[tagPrefix+gamePrefix+id]
+		parts[i] = id + ":" + l.Since + ":" + strconv.Itoa(l.Peak)
+	}
+	return strings.Join(parts, " ")
+}
+
+// continueListing is a listed game's stretch after this run: prev carried on when it was
+// listed last run (ok), a new stretch from today otherwise. rank is 0 when it did not
+// parse.
+func continueListing(prev listing, ok bool, today string, rank int) listing {
+	if !ok {
+		return listing{Since: today, Peak: rank}
+	}
+	if rank > 0 && (prev.Peak == 0 || rank < prev.Peak) {
+		prev.Peak = rank
+	}
+	return prev
+}
+
+// renderLeftContent is the body of a per-game entry whose game dropped out this run.
+func renderLeftContent(l listing, now time.Time) string {
+	var b strings.Builder
+	b.WriteString("<p>Dropped out of the BGG Hotness aggregate")
+	if since, err := time.Parse(time.DateOnly, l.Since); err == nil {
+		weeks := max(1, int(math.Round(now.Sub(since).Hours()/24/7)))
+		unit := "weeks"
+		if weeks == 1 {
+			unit = "week"
+		}
+		fmt.Fprintf(&b, " after %d %s on the list", weeks, unit)
+	}
+	if l.Peak > 0 {
+		fmt.Fprintf(&b, ", peak rank %d", l.Peak)
+	}
+	b.WriteString(".</p>")
+	return b.String()
+}
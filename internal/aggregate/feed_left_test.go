@@ -0,0 +1,95 @@
+package aggregate
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func leftRows(games ...string) [][]string {
+	var rows [][]string
+	for i, g := range games {
+		rank := string(rune('1' + i))
+		rows = append(rows, []string{rank, g, "9", "https://boardgamegeek.com/boardgame/" + g + "/", "Game " + g})
+	}
+	return rows
+}
+
+// A game that drops out gets one "dropped out" update with its weeks and peak, stays
+// frozen on the runs after, and goes back to normal when it re-enters.
+func TestPerGameLeftMarkedOnceThenFrozen(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "feed.xml")
+	week := time.Date(2026, 8, 4, 9, 0, 0, 0, time.UTC)
+	run := func(i int, games ...string) atomFeed {
+		t.Helper()
+		if _, err := updateFeedPerGame(path, testOpts, week.AddDate(0, 0, 7*i), leftRows(games...)); err != nil {
+			t.Fatal(err)
+		}
+		return parseFeed(t, path)
+	}
+
+	run(0, "100", "200")  // 200 ranked 2
+	run(1, "200", "100")  // 200 climbs to 1
+	feed := run(2, "100") // 200 drops out
+	left := findEntry(feed, tagPrefix+gamePrefix+"200")
+	if left == nil {
+		t.Fatal("the entry of the game that left is gone")
+	}
+	if want := "<p>Dropped out of the BGG Hotness aggregate after 2 weeks on the list, peak rank 1.</p>"; left.Content.Text != want {
+		t.Errorf("left content = %q, want %q", left.Content.Text, want)
+	}
+	if want := week.AddDate(0, 0, 14).Format(time.RFC3339); left.Updated != want {
+		t.Errorf("left updated = %q, want the run that noticed, %q", left.Updated, want)
+	}
+	if feed.Listed != "100:2026-08-04:1" {
+		t.Errorf("listed = %q", feed.Listed)
+	}
+
+	frozen := *left
+	feed = run(3, "100")
+	if e := findEntry(feed, tagPrefix+gamePrefix+"200"); e.Updated != frozen.Updated || e.Content != frozen.Content {
+		t.Errorf("a left entry must stay frozen: %+v, was %+v", e, frozen)
+	}
+
+	feed = run(4, "100", "200")
+	back := findEntry(feed, tagPrefix+gamePrefix+"200")
+	if !strings.HasPrefix(back.Content.Text, "<p>Rank 2 in the latest") || back.Updated != week.AddDate(0, 0, 28).Format(time.RFC3339) {
+		t.Errorf("re-entered entry = %+v", back)
+	}
+	if feed.Listed != "100:2026-08-04:1 200:2026-09-01:2" {
+		t.Errorf("listed after re-entry = %q", feed.Listed)
+	}
+	// A game with a thumbnail keeps it above the dropped-out line.
+	opts := testOpts
+	opts.Games = map[string]gameInfo{"200": {Thumbnail: gloomhavenThumb}}
+	if _, err := updateFeedPerGame(path, opts, week.AddDate(0, 0, 35), leftRows("100", "200")); err != nil {
+		t.Fatal(err)
+	}
+	feed = run(6, "100")
+	left = findEntry(feed, tagPrefix+gamePrefix+"200")
+	if want := "<p>" + renderThumbnail(gloomhavenThumb, "Game 200") + "</p><p>Dropped out of the BGG Hotness aggregate after 2 weeks on the list, peak rank 2.</p>"; left.Content.Text != want {
+		t.Errorf("left content with a thumbnail = %q, want %q", left.Content.Text, want)
+	}
+}
+
+// A feed written before the list was recorded cannot tell who left, so its first run
+// marks nothing.
+func TestPerGameLeftNeedsRecordedList(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "feed.xml")
+	entries := rawPerGameEntries()[:3]
+	writeRawFeed(t, path, entries)
+
+	if _, err := updateFeedPerGame(path, testOpts, time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC), leftRows("9999")); err != nil {
+		t.Fatal(err)
+	}
+	feed := parseFeed(t, path)
+	for _, old := range entries {
+		if e := findEntry(feed, old.ID); e == nil || e.Updated != old.Updated || e.Content != old.Content {
+			t.Errorf("entry %s was touched: %+v", old.ID, e)
+		}
+	}
+	if feed.Listed != "9999:2027-01-01:1" {
+		t.Errorf("listed = %q", feed.Listed)
+	}
+}
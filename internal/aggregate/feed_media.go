@@ -0,0 +1,61 @@
+package aggregate
+
+import (
+	"fmt"
+	"html"
+	"mime"
+	"net/url"
+	"path"
+	"strconv"
+
+	"github.com/fzerorubigd/bggo"
+)
+
+// Thumbnails. A per-game entry shows its game's BGG thumbnail as an <img> in its content
+// and as a rel="enclosure" link; a digest puts each thumbnail in front of its game's name.
+
+// gameInfo is what BGG says about a ranked game beyond the row: the feed's per-game
+// details, keyed on the BGG id as the rows carry it.
+type gameInfo struct {
+	Thumbnail string
+	// Categories are the game's BGG categories, mechanics, designers and publishers as
+	// Atom categories, in that order (see feed_category.go).
+	Categories []atomCategory
+}
+
+// gamesFromThings indexes the things rankedRows looked up for feedOptions.Games.
+func gamesFromThings(things map[int64]bggo.ThingResult) map[string]gameInfo {
+	games := make(map[string]gameInfo, len(things))
+	for id, t := range things {
+		games[strconv.FormatInt(id, 10)] = gameInfo{Thumbnail: t.Thumbnail, Categories: thingCategories(t)}
+	}
+	return games
+}
+
+// enclosureLink is the rel="enclosure" link for a thumbnail, typed from its extension
+// when that names an image type.
+func enclosureLink(thumb string) atomLink {
+	l := atomLink{Rel: "enclosure", Href: thumb}
+	if u, err := url.Parse(thumb); err == nil {
+		l.Type = mime.TypeByExtension(path.Ext(u.Path))
+	}
+	return l
+}
+
+// enclosure returns the href of the first rel="enclosure" link in links, "" if none.
+func enclosure(links []atomLink) string {
+	for _, l := range links {
+		if l.Rel == "enclosure" {
+			return l.Href
+		}
+	}
+	return ""
+}
+
+// renderThumbnail is the <img> for a thumbnail in html content, "" without one.
+func renderThumbnail(thumb, alt string) string {
+	if thumb == "" {
+		return ""
+	}
+	return fmt.Sprintf("<img src=\"%s\" alt=\"%s\">", html.EscapeString(thumb), html.EscapeString(alt))
+}
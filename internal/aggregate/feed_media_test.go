@@ -0,0 +1,71 @@
+package aggregate
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/fzerorubigd/bggo"
+)
+
+const gloomhavenThumb = "https://cf.geekdo-images.com/gloomhaven__thumb/img/pic2437871.jpg"
+
+// A per-game entry shows its thumbnail in the body and as an enclosure, and keeps it
+// through a run whose lookup missed the game, without that counting as a change.
+func TestPerGameThumbnailStickyAcrossMiss(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "feed.xml")
+	opts := feedOptions{Title: testFeedTitle, Games: gamesFromThings(map[int64]bggo.ThingResult{
+		174430: {ID: 174430, Name: "Gloomhaven", Thumbnail: gloomhavenThumb},
+	})}
+	gen := time.Date(2026, 8, 11, 9, 0, 0, 0, time.UTC)
+	if _, err := updateFeedPerGame(path, opts, gen, gloomhavenRow("1")); err != nil {
+		t.Fatal(err)
+	}
+	e := parseFeed(t, path).Entry[0]
+	if want := `<p><img src="` + gloomhavenThumb + `" alt="Gloomhaven"></p><p>Rank 1`; !strings.HasPrefix(e.Content.Text, want) {
+		t.Errorf("content = %q, want it to start with %q", e.Content.Text, want)
+	}
+	want := []atomLink{
+		{Rel: "alternate", Href: "https://boardgamegeek.com/boardgame/174430/"},
+		{Rel: "enclosure", Type: "image/jpeg", Href: gloomhavenThumb},
+	}
+	if !sameLinks(e.Link, want) {
+		t.Errorf("links = %+v, want %+v", e.Link, want)
+	}
+	if jf := readJSONFeed(t, filepath.Join(filepath.Dir(path), "feed.json")); jf.Items[0].Image != gloomhavenThumb {
+		t.Errorf("json image = %q", jf.Items[0].Image)
+	}
+
+	first, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	changed, err := updateFeedPerGame(path, testOpts, gen.AddDate(0, 0, 7), gloomhavenRow("1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if second, _ := os.ReadFile(path); len(changed) > 0 || !bytes.Equal(first, second) {
+		t.Errorf("a missed lookup must keep the thumbnail and change nothing:\n%s\n%s", first, second)
+	}
+}
+
+// A digest puts each game's thumbnail in front of its name, and a game without one just
+// has its name.
+func TestRenderContentThumbnails(t *testing.T) {
+	rows := [][]string{
+		{"1", "174430", "12", "https://boardgamegeek.com/boardgame/174430/", "Gloomhaven"},
+		{"2", "266192", "9", "https://boardgamegeek.com/boardgame/266192/", "Wingspan"},
+	}
+	body := renderContent(rows, map[string]gameInfo{"174430": {Thumbnail: gloomhavenThumb}})
+	for _, want := range []string{
+		`<li><a href="https://boardgamegeek.com/boardgame/174430/"><img src="` + gloomhavenThumb + `" alt=""> Gloomhaven</a> — 12 wins</li>`,
+		`<li><a href="https://boardgamegeek.com/boardgame/266192/">Wingspan</a> — 9 wins</li>`,
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("digest %q lacks %q", body, want)
+		}
+	}
+}
@@ -0,0 +1,123 @@
+package aggregate
+
+import (
+	"encoding/xml"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// RSS 2.0 output, for listeners whose apps understand nothing newer. Like the JSON Feed
+// it is a rendering of the FINALIZED atomFeed inside the same load/update/save cycle —
+// never read back, never updated on its own — so it carries exactly the Atom feed's
+// entries, already sorted and capped by finalizeFeed, and cannot diverge from it. It is
+// opt-in per feed (feedOptions.RSS) and written next to the Atom file with an .rss
+// extension, feed.xml -> feed.rss.
+//
+// Mapping: an item's guid is the Atom entry id, marked isPermaLink="false" because a
+// tag: URI is not a URL; pubDate is the entry's published (RFC 1123, as RSS requires);
+// link is the per-game entry's BGG page and absent on a digest, as in Atom; each Atom
+// category is a <category> with its scheme as the domain; description is the html
+// content, which encoding/xml escapes the same way it does for Atom.
+//
+// RSS requires a channel <link> and <description>, which Atom does not. link is the
+// feed's rel="alternate" page (SITE_URL), or when none is configured the BGG hotness
+// page the rankings are drawn from; description repeats the title.
+
+// rssChannelLink is the channel <link> when no site URL is configured.
+const rssChannelLink = "https://boardgamegeek.com/hotness"
+
+type rssFeed struct {
+	XMLName xml.Name   `xml:"rss"`
+	Version string     `xml:"version,attr"`
+	Channel rssChannel `xml:"channel"`
+}
+
+type rssChannel struct {
+	Title         string    `xml:"title"`
+	Link          string    `xml:"link"`
+	Description   string    `xml:"description"`
+	LastBuildDate string    `xml:"lastBuildDate,omitempty"`
+	Item          []rssItem `xml:"item"`
+}
+
+type rssItem struct {
+	Title       string        `xml:"title"`
+	Link        string        `xml:"link,omitempty"`
+	GUID        rssGUID       `xml:"guid"`
+	PubDate     string        `xml:"pubDate,omitempty"`
+	Category    []rssCategory `xml:"category"`
+	Description string        `xml:"description"`
+}
+
+type rssCategory struct {
+	Domain string `xml:"domain,attr,omitempty"`
+	Value  string `xml:",chardata"`
+}
+
+type rssGUID struct {
+	IsPermaLink string `xml:"isPermaLink,attr"`
+	Value       string `xml:",chardata"`
+}
+
+// rssFeedPath is the RSS file for the Atom feed at path: the same name with an .rss
+// extension.
+func rssFeedPath(path string) string {
+	return strings.TrimSuffix(path, filepath.Ext(path)) + ".rss"
+}
+
+// rssDate converts an Atom RFC 3339 timestamp to the RFC 1123 form RSS uses. A value
+// that does not parse is dropped rather than passed through: pubDate and lastBuildDate
+// are optional, and a malformed one is worse for a reader than none.
+func rssDate(atom string) string {
+	t, err := time.Parse(time.RFC3339, atom)
+	if err != nil {
+		return ""
+	}
+	return t.UTC().Format(time.RFC1123Z)
+}
+
+func toRSSFeed(feed atomFeed) rssFeed {
+	ch := rssChannel{
+		Title:         feed.Title,
+		Link:          rssChannelLink,
+		Description:   feed.Title,
+		LastBuildDate: rssDate(feed.Updated),
+	}
+	for _, l := range feed.Link {
+		if l.Rel == "alternate" {
+			ch.Link = l.Href
+		}
+	}
+	for _, e := range feed.Entry {
+		item := rssItem{
+			Title:       e.Title,
+			GUID:        rssGUID{IsPermaLink: "false", Value: e.ID},
+			PubDate:     rssDate(e.Published),
+			Description: e.Content.Text,
+		}
+		for _, l := range e.Link {
+			if l.Rel == "alternate" || l.Rel == "" {
+				item.Link = l.Href
+				break
+			}
+		}
+		for _, c := range e.Category {
+			item.Category = append(item.Category, rssCategory{Domain: c.Scheme, Value: c.Term})
+		}
+		ch.Item = append(ch.Item, item)
+	}
+	return rssFeed{Version: "2.0", Channel: ch}
+}
+
+// saveRSSFeed writes feed as RSS 2.0 to path when it changed, atomically like the Atom
+// file, and reports whether it did.
+func saveRSSFeed(path string, feed atomFeed) (bool, error) {
+	out, err := xml.MarshalIndent(toRSSFeed(feed), "", "  ")
+	if err != nil {
+		return false, fmt.Errorf("marshal rss feed: %w", err)
+	}
+	out = append([]byte(xml.Header), append(out, '\n')...)
+	return writeIfChanged(path, out)
+}
@@ -0,0 +1,98 @@
+package aggregate
+
+import (
+	"bytes"
+	"encoding/xml"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func readRSSFeed(t *testing.T, path string) rssFeed {
+	t.Helper()
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read rss feed: %v", err)
+	}
+	var rf rssFeed
+	if err := xml.Unmarshal(b, &rf); err != nil {
+		t.Fatalf("parse rss feed: %v", err)
+	}
+	return rf
+}
+
+// The RSS channel lists the same entries as the Atom feed, in the same order: guid is
+// the Atom id and not a permalink, pubDate is published in RFC 1123, and a per-game
+// item links the entry's BGG page.
+func TestRSSFeedMirrorsAtomPerGame(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "feed.xml")
+	opts := feedOptions{Title: testFeedTitle, RSS: true}
+	if _, err := updateFeedPerGame(path, opts, time.Date(2026, 8, 11, 9, 0, 0, 0, time.UTC), perGameRows()); err != nil {
+		t.Fatalf("updateFeedPerGame: %v", err)
+	}
+	atom := parseFeed(t, path)
+	rf := readRSSFeed(t, filepath.Join(filepath.Dir(path), "feed.rss"))
+
+	if rf.Version != "2.0" || rf.Channel.Title != testFeedTitle || rf.Channel.Link == "" || rf.Channel.Description == "" {
+		t.Errorf("channel = %q %+v", rf.Version, rf.Channel)
+	}
+	if rf.Channel.LastBuildDate != "Tue, 11 Aug 2026 09:00:00 +0000" {
+		t.Errorf("lastBuildDate = %q", rf.Channel.LastBuildDate)
+	}
+	if len(rf.Channel.Item) != len(atom.Entry) {
+		t.Fatalf("rss has %d items, atom %d entries", len(rf.Channel.Item), len(atom.Entry))
+	}
+	for i, e := range atom.Entry {
+		it := rf.Channel.Item[i]
+		pub, err := time.Parse(time.RFC1123Z, it.PubDate)
+		if err != nil || pub.Format(time.RFC3339) != e.Published {
+			t.Errorf("item %d pubDate = %q (%v), published %q", i, it.PubDate, err, e.Published)
+		}
+		if it.GUID.Value != e.ID || it.GUID.IsPermaLink != "false" || it.Title != e.Title ||
+			it.Description != e.Content.Text || it.Link != e.Link[0].Href {
+			t.Errorf("item %d = %+v, does not mirror entry %+v", i, it, e)
+		}
+	}
+}
+
+// Without the option no .rss is written; with it, a digest item has no link, and an
+// unchanged re-run leaves the .rss byte-identical, like the .xml.
+func TestRSSFeedOptInAndStable(t *testing.T) {
+	dir := t.TempDir()
+	pub := time.Date(2026, 7, 31, 23, 59, 59, 0, time.UTC)
+	gen := time.Date(2026, 8, 1, 14, 0, 0, 0, time.UTC)
+
+	plain := filepath.Join(dir, "feed.xml")
+	if _, err := updateFeedDigest(plain, testOpts, "Monthly - 2026-7", pub, gen, sampleRows()); err != nil {
+		t.Fatalf("updateFeedDigest: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "feed.rss")); !os.IsNotExist(err) {
+		t.Errorf("rss written without the option: %v", err)
+	}
+
+	path := filepath.Join(dir, "feed-monthly.xml")
+	rssPath := filepath.Join(dir, "feed-monthly.rss")
+	opts := feedOptions{Title: testFeedTitle, RSS: true}
+	if _, err := updateFeedDigest(path, opts, "Monthly - 2026-7", pub, gen, sampleRows()); err != nil {
+		t.Fatalf("updateFeedDigest: %v", err)
+	}
+	first, err := os.ReadFile(rssPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rf := readRSSFeed(t, rssPath); len(rf.Channel.Item) != 1 || rf.Channel.Item[0].Link != "" ||
+		rf.Channel.Item[0].GUID.Value != tagPrefix+"monthly-2026-7" {
+		t.Errorf("digest items = %+v", rf.Channel.Item)
+	}
+	if _, err := updateFeedDigest(path, opts, "Monthly - 2026-7", pub, gen, sampleRows()); err != nil {
+		t.Fatalf("re-run: %v", err)
+	}
+	second, err := os.ReadFile(rssPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(first, second) {
+		t.Errorf("an unchanged re-run must leave the rss feed byte-identical:\n%s\n%s", first, second)
+	}
+}
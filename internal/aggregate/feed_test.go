@@ -1,4 +1,4 @@
-package main
+package aggregate
 
 import (
 	"bytes"
@@ -23,6 +23,9 @@ import (
 // the path (see feedIDForPath), so tests vary the path to vary the id.
 const testFeedTitle = "Test Feed"
 
+// testOpts are the options of a plain feed: the test title and nothing optional.
+var testOpts = feedOptions{Title: testFeedTitle}
+
 func sampleRows() [][]string {
 	return [][]string{
 		{"1", "174430", "12", "https://boardgamegeek.com/boardgame/174430/", "Gloomhaven"},
@@ -40,11 +43,11 @@ func TestUpdateFeedDigestReplacesStableTitle(t *testing.T) {
 
 	pub := time.Date(2026, 12, 31, 23, 59, 59, 0, time.UTC)
 	gen1 := time.Date(2027, 1, 2, 0, 0, 0, 0, time.UTC)
-	if err := updateFeedDigest(path, testFeedTitle, "Yearly - 2026", pub, gen1, sampleRows()); err != nil {
+	if _, err := updateFeedDigest(path, testOpts, "Yearly - 2026", pub, gen1, sampleRows()); err != nil {
 		t.Fatalf("first updateFeedDigest: %v", err)
 	}
 	gen2 := time.Date(2027, 3, 5, 0, 0, 0, 0, time.UTC)
-	if err := updateFeedDigest(path, testFeedTitle, "Yearly - 2026", pub, gen2, sampleRows()); err != nil {
+	if _, err := updateFeedDigest(path, testOpts, "Yearly - 2026", pub, gen2, sampleRows()); err != nil {
 		t.Fatalf("re-dispatch updateFeedDigest: %v", err)
 	}
 
@@ -66,7 +69,7 @@ func TestDigestEntryHasNoLink(t *testing.T) {
 	path := filepath.Join(t.TempDir(), "feed.xml")
 	pub := time.Date(2026, 8, 1, 0, 0, 0, 0, time.UTC)
 	gen := time.Date(2026, 8, 1, 9, 0, 0, 0, time.UTC)
-	if err := updateFeedDigest(path, testFeedTitle, "2026-08-01_30-days", pub, gen, sampleRows()); err != nil {
+	if _, err := updateFeedDigest(path, testOpts, "2026-08-01_30-days", pub, gen, sampleRows()); err != nil {
 		t.Fatalf("updateFeedDigest: %v", err)
 	}
 	raw, err := os.ReadFile(path)
@@ -92,7 +95,7 @@ func perGameRows() [][]string {
 func TestPerGameOneEntryPerGameWithNavigableLink(t *testing.T) {
 	path := filepath.Join(t.TempDir(), "feed.xml")
 	gen := time.Date(2026, 8, 18, 9, 0, 0, 0, time.UTC)
-	if err := updateFeedPerGame(path, testFeedTitle, gen, perGameRows()); err != nil {
+	if _, err := updateFeedPerGame(path, testOpts, gen, perGameRows()); err != nil {
 		t.Fatalf("updateFeedPerGame: %v", err)
 	}
 
@@ -149,7 +152,7 @@ func TestPerGameBlankNameFallsBackToID(t *testing.T) {
 	path := filepath.Join(t.TempDir(), "feed.xml")
 	gen := time.Date(2026, 8, 18, 9, 0, 0, 0, time.UTC)
 	rows := [][]string{{"1", "999999", "3", "https://boardgamegeek.com/boardgame/999999/", ""}}
-	if err := updateFeedPerGame(path, testFeedTitle, gen, rows); err != nil {
+	if _, err := updateFeedPerGame(path, testOpts, gen, rows); err != nil {
 		t.Fatalf("updateFeedPerGame: %v", err)
 	}
 	feed := parseFeed(t, path)
@@ -170,12 +173,12 @@ func TestPerGameUpdateInPlacePreservesPublished(t *testing.T) {
 	gen1 := time.Date(2026, 8, 11, 9, 0, 0, 0, time.UTC)
 	gen2 := time.Date(2026, 8, 18, 9, 0, 0, 0, time.UTC)
 
-	if err := updateFeedPerGame(path, testFeedTitle, gen1, [][]string{
+	if _, err := updateFeedPerGame(path, testOpts, gen1, [][]string{
 		{"1", "174430", "12", "https://boardgamegeek.com/boardgame/174430/", "Gloomhaven"},
 	}); err != nil {
 		t.Fatalf("run 1: %v", err)
 	}
-	if err := updateFeedPerGame(path, testFeedTitle, gen2, [][]string{
+	if _, err := updateFeedPerGame(path, testOpts, gen2, [][]string{
 		{"1", "174430", "20", "https://boardgamegeek.com/boardgame/174430/", "Gloomhaven"},
 	}); err != nil {
 		t.Fatalf("run 2: %v", err)
@@ -206,14 +209,14 @@ func TestPerGameUnchangedRunIsByteIdenticalNoOp(t *testing.T) {
 	gen1 := time.Date(2026, 8, 11, 9, 0, 0, 0, time.UTC)
 	gen2 := time.Date(2026, 8, 18, 9, 0, 0, 0, time.UTC) // later, but identical rows
 
-	if err := updateFeedPerGame(path, testFeedTitle, gen1, perGameRows()); err != nil {
+	if _, err := updateFeedPerGame(path, testOpts, gen1, perGameRows()); err != nil {
 		t.Fatalf("run 1: %v", err)
 	}
 	first, err := os.ReadFile(path)
 	if err != nil {
 		t.Fatalf("read after run 1: %v", err)
 	}
-	if err := updateFeedPerGame(path, testFeedTitle, gen2, perGameRows()); err != nil {
+	if _, err := updateFeedPerGame(path, testOpts, gen2, perGameRows()); err != nil {
 		t.Fatalf("run 2: %v", err)
 	}
 	second, err := os.ReadFile(path)
@@ -233,13 +236,13 @@ func TestPerGameStickyTitleOnTransientBlankName(t *testing.T) {
 	gen1 := time.Date(2026, 8, 11, 9, 0, 0, 0, time.UTC)
 	gen2 := time.Date(2026, 8, 18, 9, 0, 0, 0, time.UTC)
 
-	if err := updateFeedPerGame(path, testFeedTitle, gen1, [][]string{
+	if _, err := updateFeedPerGame(path, testOpts, gen1, [][]string{
 		{"1", "266192", "9", "https://boardgamegeek.com/boardgame/266192/", "Wingspan"},
 	}); err != nil {
 		t.Fatalf("run 1: %v", err)
 	}
 	// Same rank+wins+link, but the name came back blank this run (transient miss).
-	if err := updateFeedPerGame(path, testFeedTitle, gen2, [][]string{
+	if _, err := updateFeedPerGame(path, testOpts, gen2, [][]string{
 		{"1", "266192", "9", "https://boardgamegeek.com/boardgame/266192/", ""},
 	}); err != nil {
 		t.Fatalf("run 2: %v", err)
@@ -269,7 +272,7 @@ func TestPerGameDuplicateIDInOneRun(t *testing.T) {
 		{"1", "174430", "12", "https://boardgamegeek.com/boardgame/174430/", "Gloomhaven"},
 		{"5", "174430", "3", "https://boardgamegeek.com/boardgame/174430/", "Gloomhaven"}, // same id, later in the run
 	}
-	if err := updateFeedPerGame(path, testFeedTitle, gen, rows); err != nil {
+	if _, err := updateFeedPerGame(path, testOpts, gen, rows); err != nil {
 		t.Fatalf("updateFeedPerGame: %v", err)
 	}
 	feed := parseFeed(t, path)
@@ -300,7 +303,7 @@ func TestFinalizeSortsByUpdatedThenRankUnrankedLast(t *testing.T) {
 	}}
 	rankByID := map[string]int{"r1": 1, "r2": 2} // "u" and "old" absent => +infinity
 
-	finalizeFeed(&feed, rankByID, time.Date(2026, 8, 18, 9, 0, 0, 0, time.UTC), false)
+	finalizeFeed(&feed, rankByID, time.Date(2026, 8, 18, 9, 0, 0, 0, time.UTC), false, 0, 0)
 
 	got := entryIDs(feed)
 	want := []string{"r1", "r2", "u", "old"}
@@ -316,7 +319,7 @@ func TestFinalizeSortsByUpdatedThenRankUnrankedLast(t *testing.T) {
 func TestFinalizeEmptyFeedUpdatedFallsBackToNow(t *testing.T) {
 	now := time.Date(2026, 8, 18, 9, 0, 0, 0, time.UTC)
 	feed := atomFeed{}
-	finalizeFeed(&feed, nil, now, false)
+	finalizeFeed(&feed, nil, now, false, 0, 0)
 	if got, want := feed.Updated, now.Format(time.RFC3339); got != want {
 		t.Errorf("empty feed updated = %q, want gen time %q", got, want)
 	}
@@ -334,15 +337,15 @@ func TestThreeFeedsDistinctIdentity(t *testing.T) {
 	yearly := filepath.Join(dir, "feed-yearly.xml")
 
 	genW := time.Date(2026, 8, 18, 9, 0, 0, 0, time.UTC)
-	if err := updateFeedPerGame(weekly, "Weekly Feed", genW, perGameRows()); err != nil {
+	if _, err := updateFeedPerGame(weekly, feedOptions{Title: "Weekly Feed"}, genW, perGameRows()); err != nil {
 		t.Fatalf("weekly: %v", err)
 	}
 	pubM := time.Date(2026, 8, 31, 0, 0, 0, 0, time.UTC)
-	if err := updateFeedDigest(monthly, "Monthly Feed", "2026-08-01_30-days", pubM, genW, sampleRows()); err != nil {
+	if _, err := updateFeedDigest(monthly, feedOptions{Title: "Monthly Feed"}, "2026-08-01_30-days", pubM, genW, sampleRows()); err != nil {
 		t.Fatalf("monthly: %v", err)
 	}
 	pubY := time.Date(2026, 12, 31, 23, 59, 59, 0, time.UTC)
-	if err := updateFeedDigest(yearly, "Yearly Feed", "Yearly - 2026", pubY, genW, sampleRows()); err != nil {
+	if _, err := updateFeedDigest(yearly, feedOptions{Title: "Yearly Feed"}, "Yearly - 2026", pubY, genW, sampleRows()); err != nil {
 		t.Fatalf("yearly: %v", err)
 	}
 
@@ -377,6 +380,50 @@ func TestThreeFeedsDistinctIdentity(t *testing.T) {
 	}
 }
 
+// The feed-level self, alternate and hub links come from the options on every run, the
+// way the title and id are reasserted: a changed setting replaces its link, an unset one
+// drops it, and a feed with none configured has no feed-level links at all.
+func TestFeedLinksReassertedEachRun(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "feed.xml")
+	gen := time.Date(2026, 8, 18, 9, 0, 0, 0, time.UTC)
+	opts := feedOptions{
+		Title:   testFeedTitle,
+		BaseURL: "https://example.org/bgg/",
+		SiteURL: "https://example.org/site/",
+		Hub:     "https://hub.example.org/",
+	}
+	if _, err := updateFeedPerGame(path, opts, gen, perGameRows()); err != nil {
+		t.Fatal(err)
+	}
+	want := []atomLink{
+		{Rel: "self", Type: "application/atom+xml", Href: "https://example.org/bgg/feed.xml"},
+		{Rel: "alternate", Type: "text/html", Href: "https://example.org/site/"},
+		{Rel: "hub", Href: "https://hub.example.org/"},
+	}
+	if got := parseFeed(t, path).Link; !sameLinks(got, want) {
+		t.Errorf("links = %+v, want %+v", got, want)
+	}
+
+	opts.BaseURL, opts.Hub = "https://mirror.example.org", ""
+	if _, err := updateFeedPerGame(path, opts, gen, perGameRows()); err != nil {
+		t.Fatal(err)
+	}
+	want = []atomLink{
+		{Rel: "self", Type: "application/atom+xml", Href: "https://mirror.example.org/feed.xml"},
+		{Rel: "alternate", Type: "text/html", Href: "https://example.org/site/"},
+	}
+	if got := parseFeed(t, path).Link; !sameLinks(got, want) {
+		t.Errorf("links after a settings change = %+v, want %+v", got, want)
+	}
+
+	if _, err := updateFeedPerGame(path, testOpts, gen, perGameRows()); err != nil {
+		t.Fatal(err)
+	}
+	if got := parseFeed(t, path).Link; len(got) != 0 {
+		t.Errorf("an unconfigured feed has links %+v", got)
+	}
+}
+
 // feedIDForPath derives the feed id from the file basename, and the weekly feed.xml must
 // map to the exact literal the live subscription depends on. Asserted against the literal
 // string rather than a self-comparison, so a rename (which re-identifies the feed) fails
@@ -398,7 +445,7 @@ func TestFeedIDForPathWeeklyLiteral(t *testing.T) {
 // --- Unchanged helpers / renderers --------------------------------------------------
 
 func TestRenderContent(t *testing.T) {
-	body := renderContent(sampleRows())
+	body := renderContent(sampleRows(), nil)
 	for _, want := range []string{
 		`<a href="https://boardgamegeek.com/boardgame/174430/">Gloomhaven</a>`,
 		"12 wins",
@@ -410,7 +457,7 @@ func TestRenderContent(t *testing.T) {
 	}
 
 	rows := [][]string{{"1", "5", "3", "https://boardgamegeek.com/boardgame/5/", "Tom & <b>Jerry</b>"}}
-	if got := renderContent(rows); !strings.Contains(got, "Tom &amp; &lt;b&gt;Jerry&lt;/b&gt;") {
+	if got := renderContent(rows, nil); !strings.Contains(got, "Tom &amp; &lt;b&gt;Jerry&lt;/b&gt;") {
 		t.Errorf("name with markup should be escaped; got %q", got)
 	}
 }
@@ -466,3 +513,48 @@ func entryIDs(feed atomFeed) []string {
 	}
 	return ids
 }
+
+// saveFeed returns only the files whose bytes changed, which is what decides which feed
+// URLs a run announces: the first run changes the .xml and .json, an unchanged re-run
+// nothing, and a run that only turns on RSS the new .rss alone.
+func TestUpdateFeedReportsChange(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "feed.xml")
+	gen := time.Date(2026, 8, 18, 9, 0, 0, 0, time.UTC)
+	opts := feedOptions{Title: testFeedTitle, BaseURL: "https://example.org/bgg"}
+	for i, c := range []struct {
+		rss  bool
+		want []string
+	}{
+		{false, []string{"feed.xml", "feed.json"}},
+		{false, nil},
+		{true, []string{"feed.rss"}},
+		{true, nil},
+	} {
+		opts.RSS = c.rss
+		changed, err := updateFeedPerGame(path, opts, gen, perGameRows())
+		if err != nil {
+			t.Fatal(err)
+		}
+		var names []string
+		for _, p := range changed {
+			names = append(names, filepath.Base(p))
+		}
+		if strings.Join(names, ",") != strings.Join(c.want, ",") {
+			t.Errorf("run %d: changed = %v, want %v", i, names, c.want)
+		}
+		if i == 2 {
+			changedFile := filepath.Join(dir, "changed")
+			if err := appendTopics(changedFile, feedTopics(changed, opts)); err != nil {
+				t.Fatal(err)
+			}
+			b, err := os.ReadFile(changedFile)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if want := "https://example.org/bgg/feed.rss\n"; string(b) != want {
+				t.Errorf("topics file = %q, want %q", b, want)
+			}
+		}
+	}
+}
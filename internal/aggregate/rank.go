@@ -0,0 +1,114 @@
+package aggregate
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+
+	"resenje.org/schulze"
+
+	"github.com/fzerorubigd/bgg-hotness/internal/history"
+)
+
+// Methods lists the ranking methods Rank accepts, for flag help and error messages.
+const Methods = "schulze, borda"
+
+// Ranked is one game's place in a ranking: its id and its score under the method, the
+// Schulze wins or the Borda points. Higher is better.
+type Ranked struct {
+	ID    string
+	Score int
+}
+
+// Rank orders the games on ballots ([date, id1, id2, ...] rows) best first. schulze,
+// the default and what Run publishes, rewards a game that beats each other game on
+// more days; borda simply sums points by position, so a game that is high on a few days
+// can outrank one that is steady in the middle. An empty method means schulze.
+func Rank(method string, ballots [][]string) ([]Ranked, error) {
+	switch method {
+	case "", "schulze":
+		result, err := schulzeRank(ballots)
+		if err != nil {
+			return nil, err
+		}
+		ranked := make([]Ranked, len(result))
+		for i, r := range result {
+			ranked[i] = Ranked{ID: r.Choice, Score: r.Wins}
+		}
+		return ranked, nil
+	case "borda":
+		return bordaRank(ballots), nil
+	default:
+		return nil, fmt.Errorf("unknown ranking method %q, want one of %s", method, Methods)
+	}
+}
+
+// schulzeRank ranks ballots with the Schulze method, best first.
+func schulzeRank(ballots [][]string) ([]schulze.Result[string], error) {
+	choices := options(ballots)
+	preferences := schulze.NewPreferences(len(choices))
+
+	for i := range ballots {
+		if _, err := schulze.Vote(preferences, choices, toMap(ballots[i])); err != nil {
+			return nil, err
+		}
+	}
+
+	result, _, _ := schulze.Compute(preferences, choices)
+	return result, nil
+}
+
+// bordaRank gives each game on a ballot of n games n points for first place down to 1
+// for last, and ranks by the total. Ties go to the lower id, so the order is stable.
+func bordaRank(ballots [][]string) []Ranked {
+	points := map[string]int{}
+	for _, b := range ballots {
+		if len(b) == 0 {
+			continue
+		}
+		ids := b[1:]
+		for i, id := range ids {
+			if id != "" {
+				points[id] += len(ids) - i
+			}
+		}
+	}
+	ranked := make([]Ranked, 0, len(points))
+	for id, p := range points {
+		ranked = append(ranked, Ranked{ID: id, Score: p})
+	}
+	sort.Slice(ranked, func(i, j int) bool {
+		if ranked[i].Score != ranked[j].Score {
+			return ranked[i].Score > ranked[j].Score
+		}
+		return ranked[i].ID < ranked[j].ID
+	})
+	return ranked
+}
+
+// Placed is a game's place in the ranking of a run of days.
+type Placed struct {
+	Rank  int
+	ID    int64
+	Name  string
+	Score int
+}
+
+// RankDays ranks days with Rank and names each game as it was last named on them. A
+// game without a name anywhere in days keeps an empty Name.
+func RankDays(method string, days []history.Day) ([]Placed, error) {
+	ranked, err := Rank(method, history.Ballots(days))
+	if err != nil {
+		return nil, err
+	}
+	names := history.Names(days)
+	placed := make([]Placed, 0, len(ranked))
+	for _, r := range ranked {
+		id, err := strconv.ParseInt(r.ID, 10, 64)
+		if err != nil {
+			continue
+		}
+		placed = append(placed, Placed{Rank: len(placed) + 1, ID: id, Name: names[id], Score: r.Score})
+	}
+	return placed, nil
+}
@@ -0,0 +1,47 @@
+package aggregate
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRank(t *testing.T) {
+	// A is first on two days and last on the third; B is second every day. Schulze
+	// prefers A (it beats B on two of three days), Borda ties them on points and the
+	// lower id wins the tie.
+	ballots := [][]string{
+		{"2026-08-01", "1", "2", "3"},
+		{"2026-08-02", "1", "2", "3"},
+		{"2026-08-03", "3", "2", "1"},
+	}
+	ids := func(r []Ranked) []string {
+		out := make([]string, len(r))
+		for i := range r {
+			out[i] = r[i].ID
+		}
+		return out
+	}
+
+	for _, method := range []string{"", "schulze"} {
+		got, err := Rank(method, ballots)
+		if err != nil {
+			t.Fatalf("Rank(%q): %v", method, err)
+		}
+		if want := []string{"1", "2", "3"}; !reflect.DeepEqual(ids(got), want) {
+			t.Errorf("Rank(%q) = %v, want %v", method, ids(got), want)
+		}
+	}
+
+	got, err := Rank("borda", ballots)
+	if err != nil {
+		t.Fatalf("Rank(borda): %v", err)
+	}
+	want := []Ranked{{"1", 7}, {"2", 6}, {"3", 5}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Rank(borda) = %v, want %v", got, want)
+	}
+
+	if _, err := Rank("approval", ballots); err == nil {
+		t.Error("Rank(approval) succeeded, want an unknown method error")
+	}
+}
@@ -0,0 +1,135 @@
+package aggregate
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/fzerorubigd/bgg-hotness/internal/bgg"
+	"github.com/fzerorubigd/bgg-hotness/internal/config"
+	"github.com/fzerorubigd/bgg-hotness/internal/history"
+	"github.com/fzerorubigd/bgg-hotness/internal/output"
+)
+
+// gameStats is how one game did over the ballots of a window.
+type gameStats struct {
+	ID int64
+	history.Stats
+}
+
+// computeStats summarises ballots ([date, id1, id2, ...] rows, in date order) per game,
+// most days on the list first, then best mean position.
+func computeStats(ballots [][]string) []gameStats {
+	days := history.FromBallots(ballots)
+	ids := history.IDs(days)
+	out := make([]gameStats, 0, len(ids))
+	for _, id := range ids {
+		out = append(out, gameStats{ID: id, Stats: history.Summarize(history.Game(days, id))})
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Days != out[j].Days {
+			return out[i].Days > out[j].Days
+		}
+		if out[i].Mean != out[j].Mean {
+			return out[i].Mean < out[j].Mean
+		}
+		return out[i].ID < out[j].ID
+	})
+	return out
+}
+
+// statsRows lays stats out as a table, header first. names may be nil or miss ids;
+// those rows have a blank name.
+func statsRows(stats []gameStats, names map[int64]string) [][]string {
+	rows := [][]string{{"BGGID", "Days", "Best", "Mean", "Latest", "Streak", "Name"}}
+	for _, s := range stats {
+		latest := "-"
+		if s.Latest > 0 {
+			latest = strconv.Itoa(s.Latest)
+		}
+		rows = append(rows, []string{
+			strconv.FormatInt(s.ID, 10),
+			strconv.Itoa(s.Days),
+			strconv.Itoa(s.Best),
+			fmt.Sprintf("%.1f", s.Mean),
+			latest,
+			strconv.Itoa(s.CurrentStreak),
+			names[s.ID],
+		})
+	}
+	return rows
+}
+
+// RunStats prints how each game did on the hot list over a window: days on the list,
+// best and mean position and position on the last day. It reads the same ballots
+// aggregate ranks and changes nothing. Names are looked up on BGG when BGG_TOKEN is
+// set.
+func RunStats(ctx context.Context, cfg config.Config, args []string) error {
+	fs := flag.NewFlagSet("stats", flag.ContinueOnError)
+	var (
+		documentID  string
+		pageID      int
+		exportURL   string
+		bggEndpoint string
+		days        int
+		year        int
+		month       int
+		count       int
+		out         string
+	)
+	fs.StringVar(&documentID, "document-id", cfg.DocumentID, "The document id to get the data from")
+	fs.IntVar(&pageID, "page-id", cfg.PageID, "The page id in document")
+	fs.StringVar(&exportURL, "export-endpoint", or(cfg.ExportEndpoint, defaultExportEndpoint), "Base URL of the spreadsheet CSV export, e.g. a local Sheets stand-in")
+	fs.StringVar(&bggEndpoint, "bgg-endpoint", cfg.BGGEndpoint, "Base URL to send BGG API requests to instead of boardgamegeek.com, e.g. a fixture server")
+	fs.IntVar(&days, "days", 14, "Number of days to report on, will be ignored if year is set")
+	fs.IntVar(&year, "year", 0, "Year to report on, if set, the days will be ignored")
+	fs.IntVar(&month, "month", 0, "Month to report on, if set, year should be set too")
+	fs.IntVar(&count, "count", 50, "Number of games to list, 0 for all")
+	fs.StringVar(&out, "output", "table", "Where to write the stats, KIND or KIND:FILE with KIND one of "+output.Kinds)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	sink, err := output.New(out, os.Stdout)
+	if err != nil {
+		return err
+	}
+
+	dayIn, dayOut, _ := aggregationPeriod(time.Now(), days, year, month)
+	ballots, err := getCSV(ctx, exportURL, documentID, pageID, dayIn, dayOut)
+	if err != nil {
+		return err
+	}
+	stats := computeStats(ballots)
+	if count > 0 && len(stats) > count {
+		stats = stats[:count]
+	}
+
+	var names map[int64]string
+	if token := os.Getenv("BGG_TOKEN"); token != "" {
+		c, err := bgg.NewClient(token, bggEndpoint)
+		if err != nil {
+			return err
+		}
+		ids := make([]int64, len(stats))
+		for i, s := range stats {
+			ids[i] = s.ID
+		}
+		things, err := lookupThings(ctx, c, ids)
+		if err != nil {
+			// Names are a nicety; the numbers are still worth printing.
+			fmt.Fprintf(os.Stderr, "names: %v\n", err)
+		}
+		names = make(map[int64]string, len(things))
+		for id, t := range things {
+			names[id] = t.Name
+		}
+	}
+	return sink.Write(output.Result{
+		Title: fmt.Sprintf("%d ballot(s), %d game(s)", len(ballots), len(stats)),
+		Rows:  statsRows(stats, names),
+	})
+}
@@ -0,0 +1,42 @@
+package aggregate
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/fzerorubigd/bgg-hotness/internal/history"
+)
+
+func TestComputeStats(t *testing.T) {
+	ballots := [][]string{
+		{"2026-08-01", "174430", "224517", "342942"},
+		{"2026-08-02", "224517", "174430", ""},
+		{"2026-08-03", "224517", "266192"},
+	}
+	day := func(d int) time.Time { return time.Date(2026, 8, d, 0, 0, 0, 0, time.UTC) }
+	want := []gameStats{
+		{224517, history.Stats{Days: 3, Total: 3, Best: 1, Mean: 4.0 / 3, Latest: 1, CurrentStreak: 3, LongestStreak: 3, First: day(1), Last: day(3)}},
+		{174430, history.Stats{Days: 2, Total: 3, Best: 1, Mean: 1.5, LongestStreak: 2, First: day(1), Last: day(2)}},
+		{266192, history.Stats{Days: 1, Total: 3, Best: 2, Mean: 2, Latest: 2, CurrentStreak: 1, LongestStreak: 1, First: day(3), Last: day(3)}},
+		{342942, history.Stats{Days: 1, Total: 3, Best: 3, Mean: 3, LongestStreak: 1, First: day(1), Last: day(1)}},
+	}
+	if got := computeStats(ballots); !reflect.DeepEqual(got, want) {
+		t.Errorf("computeStats =\n%+v\nwant\n%+v", got, want)
+	}
+}
+
+func TestStatsRows(t *testing.T) {
+	stats := []gameStats{
+		{224517, history.Stats{Days: 3, Best: 1, Mean: 1.5, Latest: 1, CurrentStreak: 3}},
+		{174430, history.Stats{Days: 1, Best: 2, Mean: 2}},
+	}
+	want := [][]string{
+		{"BGGID", "Days", "Best", "Mean", "Latest", "Streak", "Name"},
+		{"224517", "3", "1", "1.5", "1", "3", "Brass: Birmingham"},
+		{"174430", "1", "2", "2.0", "-", "0", ""},
+	}
+	if got := statsRows(stats, map[int64]string{224517: "Brass: Birmingham"}); !reflect.DeepEqual(got, want) {
+		t.Errorf("statsRows = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,99 @@
+package aggregate
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/fzerorubigd/bggo"
+	"resenje.org/schulze"
+
+	"github.com/fzerorubigd/bgg-hotness/internal/bgg"
+	"github.com/fzerorubigd/bgg-hotness/internal/bggtest"
+)
+
+// schulzeOrder fakes the Schulze result rankedRows reads wins from: ids in rank order,
+// wins counting down.
+func schulzeOrder(ids []int64) []schulze.Result[string] {
+	res := make([]schulze.Result[string], len(ids))
+	for i, id := range ids {
+		res[i] = schulze.Result[string]{Choice: fmt.Sprint(id), Wins: len(ids) - i}
+	}
+	return res
+}
+
+// withBGG starts a fixture server for the test and a BGG client pointed at it.
+func withBGG(t *testing.T) (*bggtest.Server, *bggo.Client) {
+	t.Helper()
+	s := bggtest.NewServer()
+	t.Cleanup(s.Close)
+	c, err := bgg.NewClient("token", s.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s, c
+}
+
+// The positional-mismatch path: BGG answers in its own order, and each row must still
+// carry the name of ITS id, with rank and wins from the Schulze order.
+func TestRankedRowsOutOfOrderResponse(t *testing.T) {
+	s, c := withBGG(t)
+	s.Shuffle(true)
+	ids := []int64{174430, 266192, 342942}
+
+	rows, _, err := rankedRows(context.Background(), c, ids, schulzeOrder(ids))
+	if err != nil {
+		t.Fatalf("rankedRows: %v", err)
+	}
+	want := [][]string{
+		{"1", "174430", "3", "https://boardgamegeek.com/boardgame/174430/", "Gloomhaven"},
+		{"2", "266192", "2", "https://boardgamegeek.com/boardgame/266192/", "Wingspan"},
+		{"3", "342942", "1", "https://boardgamegeek.com/boardgame/342942/", "Ark Nova"},
+	}
+	if fmt.Sprint(rows) != fmt.Sprint(want) {
+		t.Errorf("rows = %v\nwant %v", rows, want)
+	}
+}
+
+// The missing-id path: a dropped id keeps its row, rank and wins, with a blank name, and
+// the ids after it are not shifted onto the wrong names.
+func TestRankedRowsDroppedID(t *testing.T) {
+	s, c := withBGG(t)
+	s.Drop(266192)
+	ids := []int64{174430, 266192, 342942}
+
+	rows, _, err := rankedRows(context.Background(), c, ids, schulzeOrder(ids))
+	if err != nil {
+		t.Fatalf("rankedRows: %v", err)
+	}
+	if len(rows) != 3 {
+		t.Fatalf("got %d rows, want one per ranked id", len(rows))
+	}
+	if rows[1][1] != "266192" || rows[1][4] != "" {
+		t.Errorf("dropped id row = %v, want id 266192 with a blank name", rows[1])
+	}
+	if rows[2][4] != "Ark Nova" {
+		t.Errorf("row after the dropped id = %v, want Ark Nova", rows[2])
+	}
+}
+
+// More ids than batchSize are looked up in batches, and every row is filled.
+func TestRankedRowsBatches(t *testing.T) {
+	s, c := withBGG(t)
+	ids := make([]int64, batchSize+3)
+	for i := range ids {
+		ids[i] = int64(900000 + i) // no fixtures: every name is blank
+	}
+	ids[batchSize] = 174430
+
+	rows, _, err := rankedRows(context.Background(), c, ids, schulzeOrder(ids))
+	if err != nil {
+		t.Fatalf("rankedRows: %v", err)
+	}
+	if len(rows) != len(ids) || rows[batchSize][4] != "Gloomhaven" {
+		t.Errorf("batched rows: %d rows, row %d = %v", len(rows), batchSize, rows[batchSize])
+	}
+	if got := len(s.Requests()); got != 2 {
+		t.Errorf("thing requests = %d, want 2 batches", got)
+	}
+}
@@ -0,0 +1,75 @@
+// Package bgg holds what the commands share about reaching BoardGameGeek: the default
+// API host and the client that can be sent somewhere else, such as the fixture server in
+// bggtest.
+package bgg
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/fzerorubigd/bggo"
+)
+
+// redirectTransport rewrites requests for a BGG host onto base and passes every other
+// request through untouched.
+type redirectTransport struct {
+	base *url.URL
+	next http.RoundTripper
+}
+
+// isBGGHost reports whether host is one the BGG client talks to: boardgamegeek.com,
+// its subdomains, and the geekdo.com API and image hosts.
+func isBGGHost(host string) bool {
+	host = strings.ToLower(host)
+	for _, d := range []string{"boardgamegeek.com", "geekdo.com"} {
+		if host == d || strings.HasSuffix(host, "."+d) {
+			return true
+		}
+	}
+	return false
+}
+
+func (t redirectTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	if !isBGGHost(r.URL.Hostname()) {
+		return t.next.RoundTrip(r)
+	}
+	// RoundTrip must not modify the caller's request.
+	r = r.Clone(r.Context())
+	r.URL.Scheme = t.base.Scheme
+	r.URL.Host = t.base.Host
+	r.URL.Path = strings.TrimSuffix(t.base.Path, "/") + r.URL.Path
+	r.URL.RawPath = ""
+	r.Host = t.base.Host
+	return t.next.RoundTrip(r)
+}
+
+// Redirect returns a RoundTripper that sends requests for BGG hosts to base (a scheme
+// and host, optionally with a path prefix) via next, and all other requests to next
+// unchanged.
+func Redirect(base string, next http.RoundTripper) (http.RoundTripper, error) {
+	u, err := url.Parse(base)
+	if err != nil {
+		return nil, fmt.Errorf("bgg endpoint %q: %w", base, err)
+	}
+	if u.Scheme == "" || u.Host == "" {
+		return nil, fmt.Errorf("bgg endpoint %q: want an absolute URL such as http://127.0.0.1:8080", base)
+	}
+	return redirectTransport{base: u, next: next}, nil
+}
+
+// NewClient returns a BGG client that sends its requests to base instead of the BGG
+// hosts, or to the BGG hosts themselves when base is empty. The redirect lives in the
+// client's own http.Client, so it reaches that client alone: other clients, and anything
+// else on http.DefaultTransport, are unaffected however many commands set one up.
+func NewClient(token, base string, opts ...bggo.Option) (*bggo.Client, error) {
+	if base != "" {
+		rt, err := Redirect(base, http.DefaultTransport)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, bggo.WithHTTPClient(&http.Client{Transport: rt}))
+	}
+	return bggo.NewClient(token, opts...), nil
+}
@@ -0,0 +1,67 @@
+package bgg
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// BGG requests land on the override with their path and query intact; other hosts are
+// left alone, so the spreadsheet export still reaches Google.
+func TestRedirect(t *testing.T) {
+	var got string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.URL.String()
+		_, _ = io.WriteString(w, "ok")
+	}))
+	defer srv.Close()
+
+	rt, err := Redirect(srv.URL+"/prefix", http.DefaultTransport)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := &http.Client{Transport: rt}
+	for _, u := range []string{
+		"https://boardgamegeek.com/xmlapi2/thing?id=1,2",
+		"https://www.boardgamegeek.com/xmlapi2/thing?id=1,2",
+		"https://api.geekdo.com/xmlapi2/thing?id=1,2",
+	} {
+		got = ""
+		resp, err := c.Get(u)
+		if err != nil {
+			t.Fatalf("GET %s: %v", u, err)
+		}
+		resp.Body.Close()
+		if got != "/prefix/xmlapi2/thing?id=1,2" {
+			t.Errorf("GET %s reached %q", u, got)
+		}
+	}
+
+	if isBGGHost("spreadsheets.google.com") || isBGGHost("notboardgamegeek.com") {
+		t.Error("only BGG hosts may be redirected")
+	}
+}
+
+func TestRedirectRejectsRelativeBase(t *testing.T) {
+	if _, err := Redirect("127.0.0.1:8080", http.DefaultTransport); err == nil {
+		t.Error("a base without a scheme should be rejected")
+	}
+}
+
+// A client pointed elsewhere keeps the redirect to itself: the process-wide transport is
+// the one it started with, however many clients are set up.
+func TestNewClientLeavesDefaultTransport(t *testing.T) {
+	before := http.DefaultTransport
+	for range 2 {
+		if _, err := NewClient("token", "http://127.0.0.1:1"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if http.DefaultTransport != before {
+		t.Error("NewClient replaced http.DefaultTransport")
+	}
+	if _, err := NewClient("token", "127.0.0.1:1"); err == nil {
+		t.Error("NewClient accepted an endpoint without a scheme")
+	}
+}
@@ -0,0 +1,163 @@
+// Package bggtest is a fixture-driven stand-in for the BGG XML API2 endpoints the
+// commands call: /xmlapi2/hot and /xmlapi2/thing. Point a client at it with
+// bgg.NewClient (or bgg.Redirect) and it answers from recorded responses instead of
+// boardgamegeek.com.
+//
+// It reproduces the upstream behaviours the commands have to survive: thing returns
+// items in its own order rather than the requested one (Shuffle), silently drops
+// retired or invalid ids (Drop, and any id without a fixture), and answers 429 when
+// a client is being throttled (Throttle).
+package bggtest
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+)
+
+//go:embed fixtures
+var fixtures embed.FS
+
+// Server is a fake BGG XML API2.
+type Server struct {
+	*httptest.Server
+
+	fsys fs.FS
+
+	mu        sync.Mutex
+	dropped   map[string]bool
+	throttled int
+	shuffle   bool
+	requests  []*url.URL
+}
+
+// NewServer starts a Server answering from the fixtures shipped with this package: a
+// five-game hot list and a thing record for each of those games. Close it when done.
+func NewServer() *Server {
+	sub, err := fs.Sub(fixtures, "fixtures")
+	if err != nil {
+		panic(err)
+	}
+	return NewServerFS(sub)
+}
+
+// NewServerFS starts a Server answering from fsys, which holds hot.xml (a complete
+// hot response) and thing/<id>.xml (one <item> element per game).
+func NewServerFS(fsys fs.FS) *Server {
+	s := &Server{fsys: fsys, dropped: map[string]bool{}}
+	s.Server = httptest.NewServer(s)
+	return s
+}
+
+// Drop makes thing leave ids out of its responses, as BGG does for a retired id.
+func (s *Server) Drop(ids ...int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, id := range ids {
+		s.dropped[fmt.Sprint(id)] = true
+	}
+}
+
+// Shuffle makes thing return items in descending id order instead of request order,
+// so a caller that assumes the response lines up with its request gets it wrong.
+func (s *Server) Shuffle(on bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.shuffle = on
+}
+
+// Throttle makes the next n requests answer 429 Too Many Requests with a Retry-After.
+func (s *Server) Throttle(n int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.throttled = n
+}
+
+// Requests returns the URLs requested so far, in order, including throttled ones.
+func (s *Server) Requests() []*url.URL {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]*url.URL(nil), s.requests...)
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.requests = append(s.requests, r.URL)
+
+	if s.throttled > 0 {
+		s.throttled--
+		w.Header().Set("Retry-After", "1")
+		w.Header().Set("Content-Type", "text/xml; charset=utf-8")
+		w.WriteHeader(http.StatusTooManyRequests)
+		fmt.Fprint(w, `<?xml version="1.0" encoding="utf-8"?><error><message>Rate limit exceeded.</message></error>`)
+		return
+	}
+
+	switch strings.TrimSuffix(r.URL.Path, "/") {
+	case "/xmlapi2/hot":
+		s.hot(w)
+	case "/xmlapi2/thing":
+		s.thing(w, r)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func (s *Server) hot(w http.ResponseWriter) {
+	b, err := fs.ReadFile(s.fsys, "hot.xml")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "text/xml; charset=utf-8")
+	_, _ = w.Write(b)
+}
+
+func (s *Server) thing(w http.ResponseWriter, r *http.Request) {
+	var ids []string
+	for _, id := range strings.Split(r.URL.Query().Get("id"), ",") {
+		if id = strings.TrimSpace(id); id != "" {
+			ids = append(ids, id)
+		}
+	}
+	if len(ids) == 0 {
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprint(w, `<?xml version="1.0" encoding="utf-8"?><error><message>Invalid ID</message></error>`)
+		return
+	}
+	if s.shuffle {
+		sort.Slice(ids, func(i, j int) bool {
+			if len(ids[i]) != len(ids[j]) {
+				return len(ids[i]) > len(ids[j])
+			}
+			return ids[i] > ids[j]
+		})
+	}
+
+	var b bytes.Buffer
+	b.WriteString(`<?xml version="1.0" encoding="utf-8"?>` + "\n")
+	b.WriteString(`<items termsofuse="https://boardgamegeek.com/xmlapi/termsofuse">` + "\n")
+	for _, id := range ids {
+		if s.dropped[id] {
+			continue
+		}
+		item, err := fs.ReadFile(s.fsys, path.Join("thing", id+".xml"))
+		if err != nil {
+			// No fixture is the same as an id BGG does not know: left out, no error.
+			continue
+		}
+		b.Write(item)
+	}
+	b.WriteString("</items>\n")
+	w.Header().Set("Content-Type", "text/xml; charset=utf-8")
+	_, _ = w.Write(b.Bytes())
+}
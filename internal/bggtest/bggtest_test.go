@@ -0,0 +1,87 @@
+package bggtest
+
+import (
+	"encoding/xml"
+	"net/http"
+	"testing"
+)
+
+type items struct {
+	Item []struct {
+		ID   string `xml:"id,attr"`
+		Name []struct {
+			Type  string `xml:"type,attr"`
+			Value string `xml:"value,attr"`
+		} `xml:"name"`
+	} `xml:"item"`
+}
+
+func get(t *testing.T, url string) (int, items) {
+	t.Helper()
+	resp, err := http.Get(url)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	var res items
+	if resp.StatusCode == http.StatusOK {
+		if err := xml.NewDecoder(resp.Body).Decode(&res); err != nil {
+			t.Fatalf("response is not XML: %v", err)
+		}
+	}
+	return resp.StatusCode, res
+}
+
+func ids(res items) []string {
+	var out []string
+	for _, it := range res.Item {
+		out = append(out, it.ID)
+	}
+	return out
+}
+
+func TestHot(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+	code, res := get(t, s.URL+"/xmlapi2/hot?type=boardgame")
+	if code != http.StatusOK || len(res.Item) != 5 || res.Item[0].ID != "342942" {
+		t.Errorf("hot = %d %v", code, ids(res))
+	}
+}
+
+// thing in request order, then shuffled, then with a dropped id and an id that has no
+// fixture: both are left out without an error, as BGG does.
+func TestThingOrderAndDrops(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+	u := s.URL + "/xmlapi2/thing?id=174430,266192,342942,1"
+
+	if _, res := get(t, u); len(res.Item) != 3 || res.Item[0].ID != "174430" || res.Item[0].Name[0].Value != "Gloomhaven" {
+		t.Errorf("thing = %v", ids(res))
+	}
+	s.Shuffle(true)
+	if _, res := get(t, u); len(res.Item) != 3 || res.Item[0].ID != "342942" {
+		t.Errorf("shuffled thing = %v, want descending ids", ids(res))
+	}
+	s.Drop(266192)
+	if _, res := get(t, u); len(res.Item) != 2 {
+		t.Errorf("thing with a dropped id = %v", ids(res))
+	}
+}
+
+func TestThrottle(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+	s.Throttle(2)
+	for i := 0; i < 2; i++ {
+		if code, _ := get(t, s.URL+"/xmlapi2/hot"); code != http.StatusTooManyRequests {
+			t.Errorf("request %d: status %d, want 429", i, code)
+		}
+	}
+	if code, _ := get(t, s.URL+"/xmlapi2/hot"); code != http.StatusOK {
+		t.Errorf("after the throttle window: status %d, want 200", code)
+	}
+	if got := len(s.Requests()); got != 3 {
+		t.Errorf("Requests() recorded %d, want 3", got)
+	}
+}
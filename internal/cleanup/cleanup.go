@@ -0,0 +1,185 @@
+// Package cleanup is the cleanup subcommand: it removes the worksheets the retention
+// policy no longer keeps.
+package cleanup
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"google.golang.org/api/sheets/v4"
+
+	"github.com/fzerorubigd/bgg-hotness/internal/config"
+	"github.com/fzerorubigd/bgg-hotness/internal/gsheet"
+	"github.com/fzerorubigd/bgg-hotness/internal/output"
+	"github.com/fzerorubigd/bgg-hotness/internal/sheetsclient"
+	"github.com/fzerorubigd/bgg-hotness/internal/snapshot"
+)
+
+// archiveWorksheet copies a worksheet's rows into the snapshot store. Values are read
+// as formatted strings, the same text the sheet shows, so the archive reads back as the
+// rows hotness wrote.
+func archiveWorksheet(ctx context.Context, srv *sheets.Service, spreadsheetId, title string, store snapshot.Store) error {
+	vr, err := srv.Spreadsheets.Values.Get(spreadsheetId, sheetRange(title, "A1:E")).Context(ctx).Do()
+	if err != nil {
+		return err
+	}
+	rows := make([][]string, len(vr.Values))
+	for i := range vr.Values {
+		rows[i] = make([]string, len(vr.Values[i]))
+		for j := range vr.Values[i] {
+			rows[i][j] = fmt.Sprint(vr.Values[i][j])
+		}
+	}
+	return store.Write(title, rows)
+}
+
+// removals turns the plan into removeWorksheet commands. With an archive configured, a
+// worksheet is only removed once its copy is on disk: a failed copy keeps the worksheet
+// for the next run instead of deleting data the archive was supposed to hold.
+func removals(ctx context.Context, srv *sheets.Service, spreadsheetId string, plan []decision, archiveDir string) gsheet.List {
+	var commands gsheet.List
+	for _, d := range plan {
+		if !d.Remove {
+			continue
+		}
+		if archiveDir != "" {
+			if err := archiveWorksheet(ctx, srv, spreadsheetId, d.Title, snapshot.Store{Dir: archiveDir}); err != nil {
+				fmt.Fprintf(os.Stderr, "archive %q: %v (worksheet kept)\n", d.Title, err)
+				continue
+			}
+		}
+		commands = append(commands, gsheet.RemoveWorksheet{WorksheetTitle: d.Title})
+	}
+	return commands
+}
+
+// sheetRange builds an A1 range on a worksheet, quoting the title so titles with spaces
+// or punctuation (Monthly - 2026-3) are addressed correctly.
+func sheetRange(title, cells string) string {
+	return gsheet.QuoteTitle(title) + "!" + cells
+}
+
+// Run plans which worksheets the retention policy removes and writes the commands that
+// remove them, archiving each one first when an archive is configured.
+func Run(ctx context.Context, cfg config.Config, args []string) error {
+	fs := flag.NewFlagSet("cleanup", flag.ContinueOnError)
+	var (
+		spreadsheetId string
+		pageID        int
+		archiveDir    string
+		policyFile    string
+		dryRun        bool
+		out           string
+		sheetsConfig  = sheetsclient.ConfigFromEnv()
+		policy        = defaultRetention()
+		flagPolicy    = defaultRetention()
+	)
+	sheetsConfig.Endpoint = cfg.SheetsEndpoint
+	fs.StringVar(&spreadsheetId, "document-id", cfg.DocumentID, "The document id to get the data from")
+	fs.IntVar(&pageID, "page-id", cfg.PageID, "The page id in document")
+	fs.IntVar(&flagPolicy.DailyDays, "days", policy.DailyDays, "Number of days to keep every daily worksheet (clamped to [7, 90])")
+	fs.IntVar(&flagPolicy.MonthStartDays, "month-start-days", policy.MonthStartDays, "Number of days to keep the daily worksheet of the first of each month, 0 keeps it as long as the other dailies")
+	fs.IntVar(&flagPolicy.AggregateWeeks, "aggregate-weeks", policy.AggregateWeeks, "Number of weeks to keep rolling aggregate worksheets (YYYY-MM-DD_N-days), 0 keeps them as long as the dailies")
+	fs.IntVar(&flagPolicy.MonthlyDays, "monthly-days", policy.MonthlyDays, "Number of days after its month ends to keep a \"Monthly - \" worksheet, 0 keeps it forever")
+	fs.IntVar(&flagPolicy.YearlyDays, "yearly-days", policy.YearlyDays, "Number of days after its year ends to keep a \"Yearly - \" worksheet, 0 keeps it forever")
+	fs.StringVar(&policyFile, "retention-config", os.Getenv("RETENTION_CONFIG"), "YAML file with the retention policy; flags given explicitly override it")
+	fs.StringVar(&archiveDir, "archive-dir", cfg.ArchiveDir, "Directory to archive each worksheet to (as CSV) before it is removed, empty to disable")
+	fs.StringVar(&sheetsConfig.Endpoint, "sheets-endpoint", sheetsConfig.Endpoint, "Sheets API base URL, e.g. a local stand-in (default the Google API)")
+	fs.BoolVar(&dryRun, "dry-run", false, "Print the retention plan and exit, without archiving or emitting any command")
+	fs.StringVar(&out, "output", output.Default(), "Where to write the removals, KIND or KIND:FILE with KIND one of "+output.Kinds)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	sink, err := output.New(out, os.Stdout)
+	if err != nil {
+		return err
+	}
+
+	// Precedence is defaults, then the retention section of the shared config, then
+	// the retention config file, then flags given on the command line, so a workflow
+	// can keep the policy in a file and still override one tier.
+	if !cfg.Retention.IsZero() {
+		if err := cfg.Retention.Decode(&policy); err != nil {
+			return fmt.Errorf("retention in config: %w", err)
+		}
+	}
+	if policyFile != "" {
+		if err := loadRetention(policyFile, &policy); err != nil {
+			return err
+		}
+	}
+	fs.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "days":
+			policy.DailyDays = flagPolicy.DailyDays
+		case "month-start-days":
+			policy.MonthStartDays = flagPolicy.MonthStartDays
+		case "aggregate-weeks":
+			policy.AggregateWeeks = flagPolicy.AggregateWeeks
+		case "monthly-days":
+			policy.MonthlyDays = flagPolicy.MonthlyDays
+		case "yearly-days":
+			policy.YearlyDays = flagPolicy.YearlyDays
+		}
+	})
+
+	srv, err := sheetsclient.New(ctx, sheetsConfig)
+	if err != nil {
+		return fmt.Errorf("unable to retrieve Sheets client: %w", err)
+	}
+
+	sp, err := srv.Spreadsheets.Get(spreadsheetId).Context(ctx).Do()
+	if err != nil {
+		return err
+	}
+
+	titles := make([]string, 0, len(sp.Sheets))
+	for _, sh := range sp.Sheets {
+		titles = append(titles, sh.Properties.Title)
+	}
+	plan := planRetention(time.Now(), titles, policy)
+	if dryRun {
+		rows, err := rowCounts(ctx, srv, spreadsheetId, plan)
+		if err != nil {
+			// The counts are informational; the plan is still worth printing.
+			fmt.Fprintf(os.Stderr, "row counts: %v\n", err)
+		}
+		return printPlan(os.Stdout, plan, rows)
+	}
+
+	commands := removals(ctx, srv, spreadsheetId, plan, archiveDir)
+
+	// To make sure the commands are never empty
+	commands = append(commands, gsheet.GetData{
+		MinCol:         1,
+		Range:          "Aggregate!A1",
+		WorksheetTitle: "Aggregate",
+	})
+	rows := removalRows(plan, commands)
+	return sink.Write(output.Result{
+		Title:    fmt.Sprintf("Removing %d worksheet(s)", len(rows)-1),
+		Rows:     rows,
+		Commands: commands,
+	})
+}
+
+// removalRows lists the worksheets commands removes with the reason the plan gives,
+// for the readable outputs.
+func removalRows(plan []decision, commands gsheet.List) [][]string {
+	removed := map[string]bool{}
+	for _, c := range commands {
+		if _, ok := c.(gsheet.RemoveWorksheet); ok {
+			removed[c.Worksheet()] = true
+		}
+	}
+	rows := [][]string{{"Worksheet", "Kind", "Date", "Reason"}}
+	for _, d := range plan {
+		if removed[d.Title] {
+			rows = append(rows, []string{d.Title, d.Kind.String(), d.Date.Format(time.DateOnly), d.Reason})
+		}
+	}
+	return rows
+}
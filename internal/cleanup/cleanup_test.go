@@ -0,0 +1,57 @@
+package cleanup
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/fzerorubigd/bgg-hotness/internal/gsheet"
+	"github.com/fzerorubigd/bgg-hotness/internal/sheetsclient"
+	"github.com/fzerorubigd/bgg-hotness/internal/sheetstest"
+	"github.com/fzerorubigd/bgg-hotness/internal/snapshot"
+)
+
+// End to end against the Sheets stand-in: an expired daily is archived with its full
+// rows and only then removed; a worksheet that cannot be read is kept, not removed
+// unarchived.
+func TestRemovalsArchiveBeforeRemove(t *testing.T) {
+	s := sheetstest.NewServer()
+	defer s.Close()
+	rows := [][]string{
+		{"Rank", "BGGID", "Change", "Link", "Name"},
+		{"1", "174430", "2", "https://boardgamegeek.com/boardgame/174430/", "Gloomhaven"},
+	}
+	s.AddSheet("doc", "Aggregate", [][]string{{"Date", "1"}})
+	s.AddSheet("doc", "2026-08-03", rows)
+	s.AddSheet("doc", "2026-10-18", rows)
+
+	ctx := context.Background()
+	srv, err := sheetsclient.New(ctx, sheetsclient.Config{Endpoint: s.URL + "/"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Date(2026, 10, 19, 0, 0, 0, 0, time.UTC)
+	// "2026-08-04" is in the plan but not on the sheet, so its archive read fails.
+	plan := planRetention(now, append(s.Titles("doc"), "2026-08-04"), defaultRetention())
+
+	dir := t.TempDir()
+	commands := removals(ctx, srv, "doc", plan, dir)
+
+	if len(commands) != 1 || commands[0] != (gsheet.RemoveWorksheet{WorksheetTitle: "2026-08-03"}) {
+		t.Fatalf("commands = %+v, want one removeWorksheet for 2026-08-03", commands)
+	}
+	if err := gsheet.Validate(commands, s.Titles("doc")); err != nil {
+		t.Errorf("removals are not valid against the sheet: %v", err)
+	}
+	got, err := snapshot.Store{Dir: dir}.Read("2026-08-03")
+	if err != nil {
+		t.Fatalf("archive: %v", err)
+	}
+	if !reflect.DeepEqual(got, rows) {
+		t.Errorf("archived rows = %v, want %v", got, rows)
+	}
+	if (snapshot.Store{Dir: dir}).Exists("2026-10-18") {
+		t.Error("a kept worksheet must not be archived")
+	}
+}
@@ -0,0 +1,73 @@
+package cleanup
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"text/tabwriter"
+	"time"
+
+	"google.golang.org/api/sheets/v4"
+)
+
+// rowCounts returns the number of non-empty rows on each worksheet that the plan would
+// remove, in one batched read. Kept worksheets are not read: the plan only needs to say
+// how much data a removal takes with it.
+func rowCounts(ctx context.Context, srv *sheets.Service, spreadsheetId string, plan []decision) (map[string]int, error) {
+	var ranges, titles []string
+	for _, d := range plan {
+		if d.Remove {
+			ranges = append(ranges, sheetRange(d.Title, "A1:E"))
+			titles = append(titles, d.Title)
+		}
+	}
+	counts := make(map[string]int, len(titles))
+	if len(ranges) == 0 {
+		return counts, nil
+	}
+	resp, err := srv.Spreadsheets.Values.BatchGet(spreadsheetId).Ranges(ranges...).Context(ctx).Do()
+	if err != nil {
+		return nil, err
+	}
+	// ValueRanges come back in request order.
+	for i, vr := range resp.ValueRanges {
+		if i < len(titles) {
+			counts[titles[i]] = len(vr.Values)
+		}
+	}
+	return counts, nil
+}
+
+// printPlan writes a human-readable retention plan: the worksheets that would be removed
+// with their date and row count, then the worksheets that stay and why. It is the -dry-run
+// output, meant to be read before a policy change deletes anything.
+func printPlan(w io.Writer, plan []decision, rows map[string]int) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	var remove, keep []decision
+	for _, d := range plan {
+		if d.Remove {
+			remove = append(remove, d)
+		} else {
+			keep = append(keep, d)
+		}
+	}
+
+	fmt.Fprintf(tw, "Would remove %d worksheet(s):\n", len(remove))
+	for _, d := range remove {
+		n, ok := rows[d.Title]
+		count := "?"
+		if ok {
+			count = fmt.Sprint(n)
+		}
+		fmt.Fprintf(tw, "  %s\t%s\t%s rows\t%s\n", d.Title, d.Date.Format(time.DateOnly), count, d.Reason)
+	}
+	fmt.Fprintf(tw, "\nWould keep %d worksheet(s):\n", len(keep))
+	for _, d := range keep {
+		date := "-"
+		if !d.Date.IsZero() {
+			date = d.Date.Format(time.DateOnly)
+		}
+		fmt.Fprintf(tw, "  %s\t%s\t\t%s\n", d.Title, date, d.Reason)
+	}
+	return tw.Flush()
+}
@@ -0,0 +1,203 @@
+package cleanup
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// retention is the tiered policy cleanup applies to worksheets, one tier per sheetKind.
+// Each tier is an age after which a worksheet of that kind is removed; a worksheet whose
+// title parses to no kind is never touched, which is what keeps the Aggregate sheet and
+// anything added by hand out of reach.
+type retention struct {
+	// DailyDays keeps every daily worksheet (2026-10-12) this many days. It is
+	// clamped to [7, 90], the bounds the single -days cutoff always had.
+	DailyDays int `yaml:"daily_days"`
+	// MonthStartDays keeps the daily worksheet for the first day of each month this
+	// many days, so one snapshot per month survives the daily tier. A value below
+	// DailyDays is raised to it: a month start is never kept for less than a day.
+	MonthStartDays int `yaml:"month_start_days"`
+	// AggregateWeeks keeps the rolling aggregate results (2026-10-12_14-days) this
+	// many weeks. Zero ages them with the dailies, as the single cutoff did.
+	AggregateWeeks int `yaml:"aggregate_weeks"`
+	// MonthlyDays and YearlyDays keep the "Monthly - " and "Yearly - " results this
+	// many days after their period ends. Zero, the default, keeps them forever: they
+	// are the only copy of a period's result and no job regenerates them.
+	MonthlyDays int `yaml:"monthly_days"`
+	YearlyDays  int `yaml:"yearly_days"`
+}
+
+// defaultRetention is the single 14-day cutoff cleanup always applied: month starts and
+// rolling aggregates go with the dailies, and the period results are kept. The longer
+// tiers are opt-in.
+func defaultRetention() retention {
+	return retention{DailyDays: 14}
+}
+
+// loadRetention overlays the YAML file at path onto r. Keys absent from the file keep
+// the value already in r, so a file only has to name the tiers it changes.
+func loadRetention(path string, r *retention) error {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	if err := yaml.Unmarshal(b, r); err != nil {
+		return fmt.Errorf("parse retention config %q: %w", path, err)
+	}
+	return nil
+}
+
+// clamped returns the policy with every tier forced into its documented bounds.
+func (r retention) clamped() retention {
+	if r.DailyDays < 7 {
+		r.DailyDays = 7
+	}
+	if r.DailyDays > 90 {
+		r.DailyDays = 90
+	}
+	if r.MonthStartDays < r.DailyDays {
+		r.MonthStartDays = r.DailyDays
+	}
+	if r.AggregateWeeks < 0 {
+		r.AggregateWeeks = 0
+	}
+	if r.MonthlyDays < 0 {
+		r.MonthlyDays = 0
+	}
+	if r.YearlyDays < 0 {
+		r.YearlyDays = 0
+	}
+	return r
+}
+
+// sheetKind is what a worksheet title says the worksheet holds. Each kind has its
+// own tier in retention, so a rolling aggregate (2026-10-12_14-days) is no longer aged
+// out as if it were the daily it shares a date prefix with.
+type sheetKind int
+
+const (
+	kindOther   sheetKind = iota // anything else: the Aggregate sheet, hand-made sheets
+	kindDaily                    // 2026-10-12, written by hotness
+	kindRolling                  // 2026-10-12_14-days, written by aggregate -days
+	kindMonthly                  // Monthly - 2026-3, written by aggregate -year -month
+	kindYearly                   // Yearly - 2026, written by aggregate -year
+)
+
+func (k sheetKind) String() string {
+	switch k {
+	case kindDaily:
+		return "daily"
+	case kindRolling:
+		return "rolling aggregate"
+	case kindMonthly:
+		return "monthly"
+	case kindYearly:
+		return "yearly"
+	default:
+		return "other"
+	}
+}
+
+var (
+	dailyTitle   = regexp.MustCompile(`^([0-9]{4}-[0-9]{2}-[0-9]{2})$`)
+	rollingTitle = regexp.MustCompile(`^([0-9]{4}-[0-9]{2}-[0-9]{2})_[0-9]+-days$`)
+	monthlyTitle = regexp.MustCompile(`^Monthly - ([0-9]{4})-([0-9]{1,2})$`)
+	yearlyTitle  = regexp.MustCompile(`^Yearly - ([0-9]{4})$`)
+)
+
+// parseTitle classifies a worksheet title. date is the day the worksheet's age counts
+// from: the run date for daily and rolling sheets, and the first day AFTER the period for
+// monthly and yearly ones, since a period result is only complete once the period ends.
+// A title that matches a pattern but carries an impossible date is kindOther, so it is
+// left alone rather than aged on a guess.
+func parseTitle(title string) (kind sheetKind, date time.Time) {
+	if m := dailyTitle.FindStringSubmatch(title); m != nil {
+		if d, err := time.Parse(time.DateOnly, m[1]); err == nil {
+			return kindDaily, d
+		}
+	} else if m := rollingTitle.FindStringSubmatch(title); m != nil {
+		if d, err := time.Parse(time.DateOnly, m[1]); err == nil {
+			return kindRolling, d
+		}
+	} else if m := monthlyTitle.FindStringSubmatch(title); m != nil {
+		year, _ := strconv.Atoi(m[1])
+		month, _ := strconv.Atoi(m[2])
+		if month >= 1 && month <= 12 {
+			return kindMonthly, time.Date(year, time.Month(month)+1, 1, 0, 0, 0, 0, time.UTC)
+		}
+	} else if m := yearlyTitle.FindStringSubmatch(title); m != nil {
+		year, _ := strconv.Atoi(m[1])
+		return kindYearly, time.Date(year+1, 1, 1, 0, 0, 0, 0, time.UTC)
+	}
+	return kindOther, time.Time{}
+}
+
+// decision is what the policy says about one worksheet.
+type decision struct {
+	Title  string
+	Kind   sheetKind
+	Date   time.Time
+	Remove bool
+	Reason string
+}
+
+// keepFor is how long the policy keeps a worksheet of kind dated date, and the name of
+// the tier that decided it. A zero duration means the tier keeps it forever.
+func (r retention) keepFor(kind sheetKind, date time.Time) (time.Duration, string) {
+	day := 24 * time.Hour
+	switch kind {
+	case kindDaily:
+		if date.Day() == 1 {
+			return time.Duration(r.MonthStartDays) * day, "month start"
+		}
+		return time.Duration(r.DailyDays) * day, "daily"
+	case kindRolling:
+		if r.AggregateWeeks == 0 {
+			return time.Duration(r.DailyDays) * day, "rolling aggregate"
+		}
+		return time.Duration(r.AggregateWeeks) * 7 * day, "rolling aggregate"
+	case kindMonthly:
+		return time.Duration(r.MonthlyDays) * day, "monthly"
+	case kindYearly:
+		return time.Duration(r.YearlyDays) * day, "yearly"
+	}
+	return 0, kind.String()
+}
+
+// planRetention decides, for every worksheet title, whether the policy removes it. now
+// is injected so the plan is testable. Every title is returned, including the ones no
+// tier removes, so the caller sees every worksheet and why it stays.
+func planRetention(now time.Time, titles []string, r retention) []decision {
+	r = r.clamped()
+	res := make([]decision, 0, len(titles))
+	for _, title := range titles {
+		kind, date := parseTitle(title)
+		d := decision{Title: title, Kind: kind, Date: date}
+		if kind == kindOther {
+			d.Reason = "not a dated worksheet"
+			res = append(res, d)
+			continue
+		}
+		keep, tier := r.keepFor(kind, date)
+		switch {
+		case keep == 0:
+			d.Reason = tier + ", kept forever"
+		case date.Before(now.Add(-keep)):
+			d.Remove = true
+			d.Reason = fmt.Sprintf("%s, older than %s", tier, keepString(keep))
+		default:
+			d.Reason = fmt.Sprintf("%s, within %s", tier, keepString(keep))
+		}
+		res = append(res, d)
+	}
+	return res
+}
+
+func keepString(d time.Duration) string {
+	return fmt.Sprintf("%d days", int(d/(24*time.Hour)))
+}
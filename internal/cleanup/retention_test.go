@@ -0,0 +1,189 @@
+package cleanup
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func removed(ds []decision) map[string]bool {
+	m := make(map[string]bool, len(ds))
+	for _, d := range ds {
+		m[d.Title] = d.Remove
+	}
+	return m
+}
+
+// Each tier ages out on its own clock: a plain daily goes after DailyDays, the first of
+// the month survives until MonthStartDays, a rolling aggregate until AggregateWeeks.
+func TestPlanRetentionTiers(t *testing.T) {
+	now := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+	r := retention{DailyDays: 14, MonthStartDays: 365, AggregateWeeks: 8}
+	got := removed(planRetention(now, []string{
+		"2026-10-10",         // daily, 9 days old
+		"2026-09-20",         // daily, past 14 days
+		"2026-09-01",         // month start, well within a year
+		"2025-09-01",         // month start, past a year
+		"2026-09-14_14-days", // aggregate, 5 weeks old
+		"2026-07-06_14-days", // aggregate, past 8 weeks
+	}, r))
+
+	want := map[string]bool{
+		"2026-10-10":         false,
+		"2026-09-20":         true,
+		"2026-09-01":         false,
+		"2025-09-01":         true,
+		"2026-09-14_14-days": false,
+		"2026-07-06_14-days": true,
+	}
+	for title, w := range want {
+		if got[title] != w {
+			t.Errorf("%s: remove = %v, want %v", title, got[title], w)
+		}
+	}
+}
+
+// The default policy is the single cutoff cleanup always had: month starts and rolling
+// aggregates go after -days with the dailies, and period results stay.
+func TestDefaultRetentionIsSingleCutoff(t *testing.T) {
+	now := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+	titles := []string{"2026-10-10", "2026-09-01", "2026-10-12_14-days", "2026-09-14_14-days", "Monthly - 2026-3"}
+	want := map[string]bool{
+		"2026-10-10":         false,
+		"2026-09-01":         true,
+		"2026-10-12_14-days": false,
+		"2026-09-14_14-days": true,
+		"Monthly - 2026-3":   false,
+	}
+	got := removed(planRetention(now, titles, defaultRetention()))
+	for title, w := range want {
+		if got[title] != w {
+			t.Errorf("%s: remove = %v, want %v", title, got[title], w)
+		}
+	}
+	r := defaultRetention()
+	r.DailyDays = 40
+	if got := removed(planRetention(now, titles, r)); got["2026-09-14_14-days"] {
+		t.Error("a rolling aggregate within -days=40 was removed")
+	}
+}
+
+// Monthly/Yearly results are kept forever under a zero tier (the default), and the
+// Aggregate sheet and hand-made sheets are never removed under any policy.
+func TestPlanRetentionNeverTouchesUndatedSheets(t *testing.T) {
+	now := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	for _, d := range planRetention(now, []string{
+		"Monthly - 2024-3", "Yearly - 2024", "Aggregate", "2024-01-01 notes",
+	}, retention{DailyDays: 7, MonthStartDays: 7, AggregateWeeks: 1}) {
+		if d.Remove {
+			t.Errorf("%q must never be removed (reason %q)", d.Title, d.Reason)
+		}
+	}
+}
+
+// Every kind cleanup writes parses to its own kind; a rolling aggregate is not a daily
+// even though it shares the date prefix, and an impossible date is left as other.
+func TestParseTitle(t *testing.T) {
+	cases := []struct {
+		title string
+		kind  sheetKind
+		date  time.Time
+	}{
+		{"2026-10-12", kindDaily, time.Date(2026, 10, 12, 0, 0, 0, 0, time.UTC)},
+		{"2026-10-12_14-days", kindRolling, time.Date(2026, 10, 12, 0, 0, 0, 0, time.UTC)},
+		{"Monthly - 2026-3", kindMonthly, time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC)},
+		{"Monthly - 2026-12", kindMonthly, time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{"Yearly - 2025", kindYearly, time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{"Aggregate", kindOther, time.Time{}},
+		{"2026-13-40", kindOther, time.Time{}},
+		{"Monthly - 2026-13", kindOther, time.Time{}},
+		{"2026-10-12 (copy)", kindOther, time.Time{}},
+	}
+	for _, c := range cases {
+		kind, date := parseTitle(c.title)
+		if kind != c.kind || !date.Equal(c.date) {
+			t.Errorf("parseTitle(%q) = %v %v, want %v %v", c.title, kind, date, c.kind, c.date)
+		}
+	}
+}
+
+// Monthly and yearly results age from the END of their period under their own tier.
+func TestPlanRetentionPeriodTiers(t *testing.T) {
+	now := time.Date(2026, 10, 19, 0, 0, 0, 0, time.UTC)
+	r := defaultRetention()
+	r.MonthlyDays, r.YearlyDays = 180, 365
+	got := removed(planRetention(now, []string{
+		"Monthly - 2026-3", // ended 2026-04-01, 201 days ago
+		"Monthly - 2026-5", // ended 2026-06-01, 140 days ago
+		"Yearly - 2024",    // ended 2025-01-01
+		"Yearly - 2025",    // ended 2026-01-01, 291 days ago
+	}, r))
+	want := map[string]bool{
+		"Monthly - 2026-3": true,
+		"Monthly - 2026-5": false,
+		"Yearly - 2024":    true,
+		"Yearly - 2025":    false,
+	}
+	for title, w := range want {
+		if got[title] != w {
+			t.Errorf("%s: remove = %v, want %v", title, got[title], w)
+		}
+	}
+}
+
+// The daily tier keeps the [7, 90] bounds the single -days cutoff had, and a month start
+// is never kept for less than an ordinary daily.
+func TestRetentionClamped(t *testing.T) {
+	got := retention{DailyDays: 1, MonthStartDays: 3, AggregateWeeks: -1}.clamped()
+	if got.DailyDays != 7 || got.MonthStartDays != 7 || got.AggregateWeeks != 0 {
+		t.Errorf("low clamp = %+v", got)
+	}
+	if got := (retention{DailyDays: 400, MonthStartDays: 500, AggregateWeeks: 2}).clamped(); got.DailyDays != 90 {
+		t.Errorf("daily upper clamp = %d, want 90", got.DailyDays)
+	}
+}
+
+// A config file only overrides the tiers it names.
+func TestLoadRetentionOverlaysDefaults(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "retention.yaml")
+	if err := os.WriteFile(path, []byte("aggregate_weeks: 26\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	r := defaultRetention()
+	if err := loadRetention(path, &r); err != nil {
+		t.Fatalf("loadRetention: %v", err)
+	}
+	want := defaultRetention()
+	want.AggregateWeeks = 26
+	if r != want {
+		t.Errorf("got %+v, want %+v", r, want)
+	}
+}
+
+// The dry-run plan lists removals with their date and row count, and every kept sheet
+// with its reason; a removal whose count could not be read shows "?".
+func TestPrintPlan(t *testing.T) {
+	now := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+	plan := planRetention(now, []string{"2026-09-20", "2026-09-21", "2026-10-18", "Yearly - 2025", "Aggregate"}, defaultRetention())
+
+	var b strings.Builder
+	if err := printPlan(&b, plan, map[string]int{"2026-09-20": 51}); err != nil {
+		t.Fatal(err)
+	}
+	out := b.String()
+	for _, want := range []string{
+		"Would remove 2 worksheet(s)",
+		"2026-09-20  2026-09-20  51 rows",
+		"2026-09-21  2026-09-21  ? rows",
+		"Would keep 3 worksheet(s)",
+		"daily, within 14 days",
+		"yearly, kept forever",
+		"not a dated worksheet",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("plan missing %q:\n%s", want, out)
+		}
+	}
+}
@@ -0,0 +1,80 @@
+// Package cli is what the bgg-hotness subcommands share as programs: the signal
+// handling, the config and timezone setup, and the BGG token check. Each subcommand is
+// a RunFunc, run either by the bgg-hotness binary or on its own by Main.
+package cli
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/fzerorubigd/bgg-hotness/internal/config"
+)
+
+// RunFunc is a subcommand. args are the arguments after the subcommand name; it parses
+// them with its own flag set, whose defaults come from cfg.
+type RunFunc func(ctx context.Context, cfg config.Config, args []string) error
+
+// Context returns a context cancelled on the signals a CI runner or a terminal sends to
+// stop a run.
+func Context() (context.Context, context.CancelFunc) {
+	return signal.NotifyContext(context.Background(),
+		syscall.SIGINT,
+		syscall.SIGTERM,
+		syscall.SIGQUIT,
+		syscall.SIGABRT)
+}
+
+// Setup applies the parts of cfg that are process-wide. The timezone becomes time.Local,
+// so "today" and the aggregation windows are counted in it everywhere without each
+// subcommand threading a location through.
+func Setup(cfg config.Config) error {
+	loc, err := cfg.Location()
+	if err != nil {
+		return err
+	}
+	time.Local = loc
+	return nil
+}
+
+// Main runs one subcommand as a whole program, with the config file named by
+// BGG_HOTNESS_CONFIG. It is what keeps `go run ./aggregate` and the other
+// per-command directories working alongside the bgg-hotness binary.
+func Main(name string, run RunFunc) {
+	cfg, err := config.Load(os.Getenv(config.EnvFile))
+	if err == nil {
+		err = Setup(cfg)
+	}
+	if err == nil {
+		ctx, cnl := Context()
+		err = run(ctx, cfg, os.Args[1:])
+		cnl()
+	}
+	Exit(name, err)
+}
+
+// Exit ends the program for the error a subcommand returned: status 0 for nil and for
+// -h (the flag set has already printed the usage), 1 otherwise.
+func Exit(name string, err error) {
+	switch {
+	case err == nil, errors.Is(err, flag.ErrHelp):
+		os.Exit(0)
+	default:
+		fmt.Fprintf(os.Stderr, "%s: %v\n", name, err)
+		os.Exit(1)
+	}
+}
+
+// BGGToken returns the BGG API token from BGG_TOKEN.
+func BGGToken() (string, error) {
+	token := os.Getenv("BGG_TOKEN")
+	if token == "" {
+		return "", errors.New("BGG_TOKEN is not set")
+	}
+	return token, nil
+}
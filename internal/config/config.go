@@ -0,0 +1,172 @@
+// Package config is the settings the bgg-hotness subcommands share: which spreadsheet
+// to read and write, where the feeds live, the timezone a day is counted in and
+// cleanup's retention policy. They come from an optional YAML file, then from the
+// environment variables the workflows already set, then from flags, each layer
+// overriding the one before for the settings it names.
+package config
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// EnvFile names the environment variable holding the config file path, used when no
+// -config flag is given.
+const EnvFile = "BGG_HOTNESS_CONFIG"
+
+// Config is the shared configuration. The zero value is usable: every field has a
+// working default or means "not configured".
+type Config struct {
+	// DocumentID is the spreadsheet id (DOCUMENT_ID).
+	DocumentID string `yaml:"document_id"`
+	// PageID is the gid of the Aggregate worksheet aggregate exports (PAGE_ID).
+	PageID int `yaml:"page_id"`
+	// Timezone is the IANA zone the date of a run is taken in. TZ, when set,
+	// overrides it the way it overrides the local zone of any program.
+	Timezone string `yaml:"timezone"`
+
+	// BGGEndpoint, SheetsEndpoint and ExportEndpoint point the BGG API, the Sheets
+	// API and the CSV export elsewhere, e.g. at the fakes in bggtest and sheetstest
+	// (BGG_ENDPOINT, SHEETS_ENDPOINT, SHEETS_EXPORT_ENDPOINT).
+	BGGEndpoint    string `yaml:"bgg_endpoint"`
+	SheetsEndpoint string `yaml:"sheets_endpoint"`
+	ExportEndpoint string `yaml:"export_endpoint"`
+
+	// ArchiveDir is where cleanup archives a worksheet before removing it
+	// (ARCHIVE_DIR).
+	ArchiveDir string `yaml:"archive_dir"`
+	// Feeds are the Atom feeds aggregate maintains, by name (weekly, monthly,
+	// yearly); aggregate -feed=NAME picks one.
+	Feeds map[string]Feed `yaml:"feeds"`
+	// FeedBaseURL is the public URL the feed files are served under, e.g. the feed
+	// branch's raw URL; a feed's rel="self" link is it plus the file name
+	// (FEED_BASE_URL).
+	FeedBaseURL string `yaml:"feed_base_url"`
+	// SiteURL is the page the feeds are about, their rel="alternate" link: the static
+	// site or the spreadsheet (SITE_URL).
+	SiteURL string `yaml:"site_url"`
+	// Hub is the WebSub hub the feeds name as rel="hub" (WEBSUB_HUB).
+	Hub string `yaml:"hub"`
+	// Retention is cleanup's policy, in the format of its -retention-config file.
+	// It is decoded by cleanup, which owns the policy type and its defaults.
+	Retention yaml.Node `yaml:"retention"`
+}
+
+// Feed is one feed file, its title and how else it is published.
+type Feed struct {
+	File  string `yaml:"file"`
+	Title string `yaml:"title"`
+	// RSS also writes the feed as RSS 2.0 next to the file (FEED_RSS).
+	RSS bool `yaml:"rss"`
+	// History adds each game's rank history to its per-game entry (FEED_HISTORY).
+	History bool `yaml:"history"`
+	// Cap is the most entries the feed keeps, 0 for no limit; unset keeps aggregate's
+	// default (FEED_CAP).
+	Cap *int `yaml:"cap"`
+	// MaxAgeDays moves the entries older than this many days out of the feed, 0 for no
+	// age limit (FEED_MAX_AGE_DAYS).
+	MaxAgeDays int `yaml:"max_age_days"`
+}
+
+// Load reads the config file at path, when path is not empty, and applies the
+// environment overrides on top.
+func Load(path string) (Config, error) {
+	var c Config
+	if path != "" {
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return Config{}, err
+		}
+		if err := yaml.Unmarshal(b, &c); err != nil {
+			return Config{}, fmt.Errorf("parse config %q: %w", path, err)
+		}
+	}
+	if err := c.applyEnv(); err != nil {
+		return Config{}, err
+	}
+	return c, nil
+}
+
+func (c *Config) applyEnv() error {
+	for key, dst := range map[string]*string{
+		"DOCUMENT_ID":            &c.DocumentID,
+		"TZ":                     &c.Timezone,
+		"BGG_ENDPOINT":           &c.BGGEndpoint,
+		"SHEETS_ENDPOINT":        &c.SheetsEndpoint,
+		"SHEETS_EXPORT_ENDPOINT": &c.ExportEndpoint,
+		"ARCHIVE_DIR":            &c.ArchiveDir,
+		"FEED_BASE_URL":          &c.FeedBaseURL,
+		"SITE_URL":               &c.SiteURL,
+		"WEBSUB_HUB":             &c.Hub,
+	} {
+		if v := os.Getenv(key); v != "" {
+			*dst = v
+		}
+	}
+	if v := os.Getenv("PAGE_ID"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return fmt.Errorf("PAGE_ID %q: %w", v, err)
+		}
+		c.PageID = n
+	}
+	for _, key := range []string{"FEED_RSS", "FEED_HISTORY"} {
+		if v := os.Getenv(key); v != "" {
+			if _, err := strconv.ParseBool(v); err != nil {
+				return fmt.Errorf("%s %q: %w", key, v, err)
+			}
+		}
+	}
+	for _, key := range []string{"FEED_CAP", "FEED_MAX_AGE_DAYS"} {
+		if v := os.Getenv(key); v != "" {
+			if n, err := strconv.Atoi(v); err != nil || n < 0 {
+				return fmt.Errorf("%s %q: want a count of 0 or more", key, v)
+			}
+		}
+	}
+	return nil
+}
+
+// Feed returns the feed configured under name, with FEED_FILE, FEED_TITLE, FEED_RSS,
+// FEED_HISTORY, FEED_CAP and FEED_MAX_AGE_DAYS overriding its settings, as the workflows
+// set them per job.
+func (c Config) Feed(name string) Feed {
+	f := c.Feeds[name]
+	if v := os.Getenv("FEED_FILE"); v != "" {
+		f.File = v
+	}
+	if v := os.Getenv("FEED_TITLE"); v != "" {
+		f.Title = v
+	}
+	// Load has already rejected a FEED_RSS or FEED_HISTORY that does not parse.
+	if b, err := strconv.ParseBool(os.Getenv("FEED_RSS")); err == nil {
+		f.RSS = b
+	}
+	if b, err := strconv.ParseBool(os.Getenv("FEED_HISTORY")); err == nil {
+		f.History = b
+	}
+	// Load has likewise rejected a FEED_CAP or FEED_MAX_AGE_DAYS that is not a count.
+	if n, err := strconv.Atoi(os.Getenv("FEED_CAP")); err == nil {
+		f.Cap = &n
+	}
+	if n, err := strconv.Atoi(os.Getenv("FEED_MAX_AGE_DAYS")); err == nil {
+		f.MaxAgeDays = n
+	}
+	return f
+}
+
+// Location returns the configured timezone, or time.Local when none is set.
+func (c Config) Location() (*time.Location, error) {
+	if c.Timezone == "" {
+		return time.Local, nil
+	}
+	loc, err := time.LoadLocation(c.Timezone)
+	if err != nil {
+		return nil, fmt.Errorf("timezone: %w", err)
+	}
+	return loc, nil
+}
@@ -0,0 +1,125 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+const sample = `
+document_id: doc-from-file
+page_id: 12
+timezone: Asia/Tehran
+feeds:
+  weekly:
+    file: feed.xml
+    title: BGG Hotness
+  yearly:
+    file: feed-yearly.xml
+    cap: 0
+retention:
+  daily_days: 30
+`
+
+func write(t *testing.T, body string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte(body), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func clearEnv(t *testing.T) {
+	for _, key := range []string{"DOCUMENT_ID", "PAGE_ID", "TZ", "BGG_ENDPOINT", "SHEETS_ENDPOINT", "SHEETS_EXPORT_ENDPOINT", "ARCHIVE_DIR", "FEED_FILE", "FEED_TITLE", "FEED_RSS", "FEED_HISTORY", "FEED_CAP", "FEED_MAX_AGE_DAYS", "FEED_BASE_URL", "SITE_URL", "WEBSUB_HUB"} {
+		t.Setenv(key, "")
+	}
+}
+
+func TestLoadFile(t *testing.T) {
+	clearEnv(t)
+	c, err := Load(write(t, sample))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.DocumentID != "doc-from-file" || c.PageID != 12 || c.Timezone != "Asia/Tehran" {
+		t.Errorf("config = %+v", c)
+	}
+	if f := c.Feed("weekly"); f != (Feed{File: "feed.xml", Title: "BGG Hotness"}) {
+		t.Errorf("Feed(weekly) = %+v", f)
+	}
+	if f := c.Feed("yearly"); f.Cap == nil || *f.Cap != 0 {
+		t.Errorf("Feed(yearly) = %+v, want an explicit cap of 0", f)
+	}
+	var r struct {
+		DailyDays int `yaml:"daily_days"`
+	}
+	if err := c.Retention.Decode(&r); err != nil || r.DailyDays != 30 {
+		t.Errorf("retention decodes to %+v, %v", r, err)
+	}
+	loc, err := c.Location()
+	if err != nil || loc.String() != "Asia/Tehran" {
+		t.Errorf("Location = %v, %v", loc, err)
+	}
+}
+
+// The workflows configure everything through the environment; it has to win over the
+// file so a shared config file cannot silently redirect a job.
+func TestEnvOverridesFile(t *testing.T) {
+	clearEnv(t)
+	t.Setenv("DOCUMENT_ID", "doc-from-env")
+	t.Setenv("PAGE_ID", "3")
+	t.Setenv("FEED_FILE", "/tmp/feed-monthly.xml")
+	c, err := Load(write(t, sample))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.DocumentID != "doc-from-env" || c.PageID != 3 {
+		t.Errorf("config = %+v", c)
+	}
+	if f := c.Feed("weekly"); f != (Feed{File: "/tmp/feed-monthly.xml", Title: "BGG Hotness"}) {
+		t.Errorf("Feed(weekly) = %+v", f)
+	}
+
+	t.Setenv("FEED_RSS", "true")
+	if f := c.Feed("weekly"); !f.RSS {
+		t.Errorf("FEED_RSS=true: Feed(weekly) = %+v", f)
+	}
+	t.Setenv("FEED_RSS", "sometimes")
+	if _, err := Load(""); err == nil {
+		t.Error("a FEED_RSS that is not a boolean should fail")
+	}
+	t.Setenv("FEED_RSS", "")
+
+	t.Setenv("FEED_CAP", "0")
+	t.Setenv("FEED_MAX_AGE_DAYS", "182")
+	if f := c.Feed("weekly"); f.Cap == nil || *f.Cap != 0 || f.MaxAgeDays != 182 {
+		t.Errorf("FEED_CAP=0 FEED_MAX_AGE_DAYS=182: Feed(weekly) = %+v", f)
+	}
+	t.Setenv("FEED_CAP", "-1")
+	if _, err := Load(""); err == nil {
+		t.Error("a negative FEED_CAP should fail")
+	}
+	t.Setenv("FEED_CAP", "")
+	t.Setenv("FEED_MAX_AGE_DAYS", "")
+
+	t.Setenv("PAGE_ID", "first")
+	if _, err := Load(""); err == nil {
+		t.Error("a non-numeric PAGE_ID should fail")
+	}
+}
+
+func TestNoFile(t *testing.T) {
+	clearEnv(t)
+	c, err := Load("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !c.Retention.IsZero() || c.DocumentID != "" {
+		t.Errorf("config without a file = %+v", c)
+	}
+	if loc, err := c.Location(); err != nil || loc != time.Local {
+		t.Errorf("Location = %v, %v; want time.Local", loc, err)
+	}
+}
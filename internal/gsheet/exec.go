@@ -0,0 +1,80 @@
+package gsheet
+
+import (
+	"context"
+	"fmt"
+)
+
+// Backend is a spreadsheet a List can run against. Ranges passed to it have been
+// checked by Validate; Row1 and Col1 may still be -1 (unbounded).
+type Backend interface {
+	// Worksheets returns the titles of the worksheets, in any order.
+	Worksheets(ctx context.Context) ([]string, error)
+	AddWorksheet(ctx context.Context, title string) error
+	RemoveWorksheet(ctx context.Context, title string) error
+	// Update writes data into r, starting at its top-left cell.
+	Update(ctx context.Context, r Range, data [][]string) error
+	// Append writes data below the last row with any non-empty cell, from column col
+	// (zero-based).
+	Append(ctx context.Context, title string, col int, data [][]string) error
+	// Get reads r, omitting trailing empty cells and rows as Sheets does.
+	Get(ctx context.Context, r Range) ([][]string, error)
+}
+
+// Result is the outcome of one command. Data is set for getData only.
+type Result struct {
+	Command   string     `json:"command"`
+	Worksheet string     `json:"worksheetTitle"`
+	Data      [][]string `json:"data,omitempty"`
+}
+
+// Execute validates l against the worksheets b has, then runs it in order. Nothing runs
+// if the list is invalid; otherwise it stops at the first failing command and returns
+// the results of the commands before it, which have already been applied.
+func Execute(ctx context.Context, b Backend, l List) ([]Result, error) {
+	titles, err := b.Worksheets(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := Validate(l, titles); err != nil {
+		return nil, err
+	}
+
+	results := make([]Result, 0, len(l))
+	for i, c := range l {
+		res := Result{Command: c.Name(), Worksheet: c.Worksheet()}
+		if res.Data, err = run(ctx, b, c); err != nil {
+			return results, fmt.Errorf("command %d (%s %q): %w", i, c.Name(), c.Worksheet(), err)
+		}
+		results = append(results, res)
+	}
+	return results, nil
+}
+
+func run(ctx context.Context, b Backend, c Command) ([][]string, error) {
+	switch c := c.(type) {
+	case AddWorksheet:
+		return nil, b.AddWorksheet(ctx, c.WorksheetTitle)
+	case RemoveWorksheet:
+		return nil, b.RemoveWorksheet(ctx, c.WorksheetTitle)
+	case UpdateData:
+		r, err := commandRange(c.Range, c.WorksheetTitle, c.MinCol)
+		if err != nil {
+			return nil, err
+		}
+		return nil, b.Update(ctx, r, c.Data)
+	case AppendData:
+		col := 0
+		if c.MinCol > 0 {
+			col = c.MinCol - 1
+		}
+		return nil, b.Append(ctx, c.WorksheetTitle, col, c.Data)
+	case GetData:
+		r, err := commandRange(c.Range, c.WorksheetTitle, c.MinCol)
+		if err != nil {
+			return nil, err
+		}
+		return b.Get(ctx, r)
+	}
+	return nil, fmt.Errorf("unsupported command %T", c)
+}
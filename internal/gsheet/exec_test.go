@@ -0,0 +1,129 @@
+// The executor tests are an external package because sheetstest, which plays the
+// Sheets API here, itself imports gsheet for range parsing.
+package gsheet_test
+
+import (
+	"context"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/fzerorubigd/bgg-hotness/internal/gsheet"
+	"github.com/fzerorubigd/bgg-hotness/internal/sheetsclient"
+	"github.com/fzerorubigd/bgg-hotness/internal/sheetstest"
+	"github.com/fzerorubigd/bgg-hotness/internal/snapshot"
+)
+
+var rows = [][]string{
+	{"Rank", "BGGID", "Change", "Link", "Name"},
+	{"1", "174430", "2", "https://boardgamegeek.com/boardgame/174430/", "Gloomhaven"},
+}
+
+// cycle is a daily run followed by cleanup's removal of an expired daily.
+var cycle = gsheet.List{
+	gsheet.AddWorksheet{WorksheetTitle: "2026-08-04"},
+	gsheet.UpdateData{Data: rows, MinCol: 1, Range: "2026-08-04!A1:E2", WorksheetTitle: "2026-08-04"},
+	gsheet.AppendData{Data: [][]string{{"2026-08-04", "174430"}}, MinCol: 1, WorksheetTitle: "Aggregate"},
+	gsheet.RemoveWorksheet{WorksheetTitle: "2026-07-01"},
+	gsheet.GetData{MinCol: 1, Range: "Aggregate!A1:C", WorksheetTitle: "Aggregate"},
+}
+
+var wantResults = []gsheet.Result{
+	{Command: "addWorksheet", Worksheet: "2026-08-04"},
+	{Command: "updateData", Worksheet: "2026-08-04"},
+	{Command: "appendData", Worksheet: "Aggregate"},
+	{Command: "removeWorksheet", Worksheet: "2026-07-01"},
+	{Command: "getData", Worksheet: "Aggregate", Data: [][]string{
+		{"Date", "1", "2"},
+		{"2026-08-03", "224517", "174430"},
+		{"2026-08-04", "174430"},
+	}},
+}
+
+// Both backends must run the same list to the same end state, so a local run stands
+// in for a real one.
+func TestExecuteBackends(t *testing.T) {
+	aggregate := [][]string{{"Date", "1", "2"}, {"2026-08-03", "224517", "174430"}}
+
+	s := sheetstest.NewServer()
+	defer s.Close()
+	s.AddSheet("doc", "Aggregate", aggregate)
+	s.AddSheet("doc", "2026-07-01", rows)
+	srv, err := sheetsclient.New(context.Background(), sheetsclient.Config{Endpoint: s.URL + "/"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	store := snapshot.Store{Dir: filepath.Join(t.TempDir(), "sheets")}
+	for title, r := range map[string][][]string{"Aggregate": aggregate, "2026-07-01": rows} {
+		if err := store.Write(title, r); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	backends := map[string]gsheet.Backend{
+		"sheets": gsheet.SheetsBackend{Service: srv, SpreadsheetID: "doc"},
+		"local":  gsheet.LocalBackend{Store: store},
+	}
+	for name, b := range backends {
+		ctx := context.Background()
+		results, err := gsheet.Execute(ctx, b, cycle)
+		if err != nil {
+			t.Fatalf("%s: Execute: %v", name, err)
+		}
+		if !reflect.DeepEqual(results, wantResults) {
+			t.Errorf("%s: results = %+v, want %+v", name, results, wantResults)
+		}
+		titles, err := b.Worksheets(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !sameSet(titles, []string{"Aggregate", "2026-08-04"}) {
+			t.Errorf("%s: worksheets = %v", name, titles)
+		}
+		got, err := b.Get(ctx, gsheet.Range{Title: "2026-08-04", Row1: -1, Col1: -1})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(got, rows) {
+			t.Errorf("%s: 2026-08-04 = %v, want %v", name, got, rows)
+		}
+	}
+}
+
+// An invalid list must not touch the sheet at all, not even the valid commands before
+// the bad one.
+func TestExecuteInvalidRunsNothing(t *testing.T) {
+	store := snapshot.Store{Dir: t.TempDir()}
+	if err := store.Write("Aggregate", [][]string{{"Date"}}); err != nil {
+		t.Fatal(err)
+	}
+	l := gsheet.List{
+		gsheet.AddWorksheet{WorksheetTitle: "2026-08-04"},
+		gsheet.UpdateData{Data: rows, Range: "2026-08-04!A1:E51", WorksheetTitle: "2026-08-04"},
+	}
+	_, err := gsheet.Execute(context.Background(), gsheet.LocalBackend{Store: store}, l)
+	if err == nil || !strings.Contains(err.Error(), "has 51 rows, data has 2") {
+		t.Fatalf("Execute = %v, want a shape error", err)
+	}
+	if store.Exists("2026-08-04") {
+		t.Error("the addWorksheet before the invalid command ran")
+	}
+}
+
+func sameSet(a, b []string) bool {
+	m := map[string]int{}
+	for _, s := range a {
+		m[s]++
+	}
+	for _, s := range b {
+		m[s]--
+	}
+	for _, n := range m {
+		if n != 0 {
+			return false
+		}
+	}
+	return true
+}
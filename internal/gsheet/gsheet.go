@@ -0,0 +1,203 @@
+// Package gsheet models the command lists hotness, aggregate and cleanup hand to
+// jroehl/gsheet.action: addWorksheet, updateData, appendData, removeWorksheet and
+// getData. The commands are typed here, so a producer cannot misspell an argument, and
+// a List marshals to exactly the JSON the action reads.
+//
+// A List can be checked before it leaves the process (Validate: a range that does not
+// match its data, a worksheet used before it is added) and run without the action at
+// all (Execute), against the Sheets API or a directory of CSV files.
+package gsheet
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// Command is one entry of a command list. Its fields are the command's args.
+type Command interface {
+	// Name is the action's name for the command, e.g. "addWorksheet".
+	Name() string
+	// Worksheet is the title of the worksheet the command acts on.
+	Worksheet() string
+}
+
+// The arg structs list their fields in alphabetical JSON-key order, the order the
+// map[string]interface{} args they replace were marshalled in, so the emitted JSON is
+// byte for byte what it was.
+
+// AddWorksheet creates a worksheet. It fails if the title is taken.
+type AddWorksheet struct {
+	WorksheetTitle string `json:"worksheetTitle"`
+}
+
+// UpdateData writes Data into Range, or from column MinCol of the first row when
+// Range is empty.
+type UpdateData struct {
+	Data           [][]string `json:"data"`
+	MinCol         int        `json:"minCol,omitempty"`
+	Range          string     `json:"range,omitempty"`
+	WorksheetTitle string     `json:"worksheetTitle"`
+}
+
+// AppendData writes Data below the last non-empty row, starting at column MinCol.
+type AppendData struct {
+	Data           [][]string `json:"data"`
+	MinCol         int        `json:"minCol,omitempty"`
+	WorksheetTitle string     `json:"worksheetTitle"`
+}
+
+// RemoveWorksheet deletes a worksheet.
+type RemoveWorksheet struct {
+	WorksheetTitle string `json:"worksheetTitle"`
+}
+
+// GetData reads Range, or the whole worksheet when Range is empty.
+type GetData struct {
+	MinCol         int    `json:"minCol,omitempty"`
+	Range          string `json:"range,omitempty"`
+	WorksheetTitle string `json:"worksheetTitle"`
+}
+
+func (AddWorksheet) Name() string    { return "addWorksheet" }
+func (UpdateData) Name() string      { return "updateData" }
+func (AppendData) Name() string      { return "appendData" }
+func (RemoveWorksheet) Name() string { return "removeWorksheet" }
+func (GetData) Name() string         { return "getData" }
+
+func (c AddWorksheet) Worksheet() string    { return c.WorksheetTitle }
+func (c UpdateData) Worksheet() string      { return c.WorksheetTitle }
+func (c AppendData) Worksheet() string      { return c.WorksheetTitle }
+func (c RemoveWorksheet) Worksheet() string { return c.WorksheetTitle }
+func (c GetData) Worksheet() string         { return c.WorksheetTitle }
+
+// List is a command list, run in order.
+type List []Command
+
+type envelope struct {
+	Command string          `json:"command"`
+	Args    json.RawMessage `json:"args"`
+}
+
+// MarshalJSON encodes the list as [{"command": name, "args": {...}}, ...].
+func (l List) MarshalJSON() ([]byte, error) {
+	out := make([]envelope, len(l))
+	for i, c := range l {
+		args, err := json.Marshal(c)
+		if err != nil {
+			return nil, err
+		}
+		out[i] = envelope{Command: c.Name(), Args: args}
+	}
+	return json.Marshal(out)
+}
+
+// UnmarshalJSON decodes a list in the action's format. An unknown command or an
+// unknown argument is an error, since the action would reject or ignore it.
+func (l *List) UnmarshalJSON(b []byte) error {
+	var in []envelope
+	if err := json.Unmarshal(b, &in); err != nil {
+		return err
+	}
+	out := make(List, len(in))
+	for i, e := range in {
+		var c Command
+		switch e.Command {
+		case "addWorksheet":
+			c = &AddWorksheet{}
+		case "updateData":
+			c = &UpdateData{}
+		case "appendData":
+			c = &AppendData{}
+		case "removeWorksheet":
+			c = &RemoveWorksheet{}
+		case "getData":
+			c = &GetData{}
+		default:
+			return fmt.Errorf("command %d: unknown command %q", i, e.Command)
+		}
+		dec := json.NewDecoder(bytes.NewReader(e.Args))
+		dec.DisallowUnknownFields()
+		if err := dec.Decode(c); err != nil {
+			return fmt.Errorf("command %d (%s): %w", i, e.Command, err)
+		}
+		// Store values, not pointers, so a decoded list compares equal to a built one.
+		switch c := c.(type) {
+		case *AddWorksheet:
+			out[i] = *c
+		case *UpdateData:
+			out[i] = *c
+		case *AppendData:
+			out[i] = *c
+		case *RemoveWorksheet:
+			out[i] = *c
+		case *GetData:
+			out[i] = *c
+		}
+	}
+	*l = out
+	return nil
+}
+
+// Write encodes l as the "data_array" output of a GitHub Actions step: a heredoc
+// whose delimiter is a hash of the current time, so it cannot occur in the JSON.
+// gsheet.action reads the list from that output.
+func Write(w io.Writer, l List) error {
+	x, err := json.Marshal(l)
+	if err != nil {
+		return err
+	}
+	sum := sha256.New()
+	fmt.Fprint(sum, time.Now())
+	eof := fmt.Sprintf("%x", sum.Sum(nil))
+	_, err = fmt.Fprintf(w, "data_array<<%s\n%s\n%s\n", eof, x, eof)
+	return err
+}
+
+// Read decodes a command list from r, either bare JSON or wrapped in the
+// "data_array<<EOF ... EOF" block the commands write for $GITHUB_OUTPUT, so a
+// command's stdout can be piped straight into an executor.
+func Read(r io.Reader) (List, error) {
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	b = bytes.TrimSpace(b)
+	if !bytes.HasPrefix(b, []byte("[")) {
+		first, rest, _ := bytes.Cut(b, []byte("\n"))
+		_, eof, ok := strings.Cut(string(first), "<<")
+		if !ok {
+			return nil, fmt.Errorf("command list: neither a JSON list nor a heredoc")
+		}
+		body, found := cutHeredoc(rest, eof)
+		if !found {
+			return nil, fmt.Errorf("command list: no closing %q", eof)
+		}
+		b = body
+	}
+	var l List
+	if err := json.Unmarshal(b, &l); err != nil {
+		return nil, fmt.Errorf("command list: %w", err)
+	}
+	return l, nil
+}
+
+// cutHeredoc returns the lines of b before the line equal to eof.
+func cutHeredoc(b []byte, eof string) ([]byte, bool) {
+	var body bytes.Buffer
+	s := bufio.NewScanner(bytes.NewReader(b))
+	s.Buffer(nil, len(b)+1)
+	for s.Scan() {
+		if strings.TrimSpace(s.Text()) == eof {
+			return body.Bytes(), true
+		}
+		body.Write(s.Bytes())
+		body.WriteByte('\n')
+	}
+	return nil, false
+}
@@ -0,0 +1,113 @@
+package gsheet
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// daily is the list hotness emits, trimmed to two games.
+func daily() List {
+	return List{
+		AddWorksheet{WorksheetTitle: "2026-08-01"},
+		UpdateData{
+			Data: [][]string{
+				{"Rank", "BGGID", "Change", "Link", "Name"},
+				{"1", "174430", "2", "https://boardgamegeek.com/boardgame/174430/", "Gloomhaven"},
+				{"2", "224517", "0", "https://boardgamegeek.com/boardgame/224517/", "Brass: Birmingham"},
+			},
+			MinCol:         1,
+			Range:          "2026-08-01!A1:E3",
+			WorksheetTitle: "2026-08-01",
+		},
+		AppendData{
+			Data:           [][]string{{"2026-08-01", "174430", "224517"}},
+			MinCol:         1,
+			WorksheetTitle: "Aggregate",
+		},
+	}
+}
+
+// The typed list must marshal to exactly what the map-based commands produced, since
+// the action (and anything diffing the workflow output) reads those bytes.
+func TestMarshalMatchesMapArgs(t *testing.T) {
+	l := daily()
+	legacy := []map[string]interface{}{
+		{"command": "addWorksheet", "args": map[string]interface{}{"worksheetTitle": "2026-08-01"}},
+		{"command": "updateData", "args": map[string]interface{}{
+			"minCol": 1, "data": l[1].(UpdateData).Data, "range": "2026-08-01!A1:E3", "worksheetTitle": "2026-08-01",
+		}},
+		{"command": "appendData", "args": map[string]interface{}{
+			"minCol": 1, "data": l[2].(AppendData).Data, "worksheetTitle": "Aggregate",
+		}},
+	}
+	// Struct-shaped like the old Command type, so "command" precedes "args".
+	type command struct {
+		Command string                 `json:"command"`
+		Args    map[string]interface{} `json:"args"`
+	}
+	var old []command
+	for _, m := range legacy {
+		old = append(old, command{Command: m["command"].(string), Args: m["args"].(map[string]interface{})})
+	}
+	want, err := json.Marshal(old)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := json.Marshal(l)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != string(want) {
+		t.Errorf("marshal =\n%s\nwant\n%s", got, want)
+	}
+
+	var back List
+	if err := json.Unmarshal(got, &back); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(back, l) {
+		t.Errorf("round trip = %+v, want %+v", back, l)
+	}
+}
+
+func TestUnmarshalRejectsUnknown(t *testing.T) {
+	for _, in := range []string{
+		`[{"command":"renameWorksheet","args":{"worksheetTitle":"a"}}]`,
+		`[{"command":"addWorksheet","args":{"worksheetTitel":"a"}}]`,
+	} {
+		var l List
+		if err := json.Unmarshal([]byte(in), &l); err == nil {
+			t.Errorf("Unmarshal(%s) should fail", in)
+		}
+	}
+}
+
+// Read must accept what Write emits, the form piped from a command's stdout.
+func TestReadHeredoc(t *testing.T) {
+	b, err := json.Marshal(daily())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var heredoc strings.Builder
+	if err := Write(&heredoc, daily()); err != nil {
+		t.Fatal(err)
+	}
+	for name, in := range map[string]string{
+		"heredoc": heredoc.String(),
+		"bare":    string(b) + "\n",
+	} {
+		l, err := Read(strings.NewReader(in))
+		if err != nil {
+			t.Errorf("%s: %v", name, err)
+			continue
+		}
+		if !reflect.DeepEqual(l, daily()) {
+			t.Errorf("%s: Read = %+v", name, l)
+		}
+	}
+	if _, err := Read(strings.NewReader("data_array<<abc123\n[]\n")); err == nil {
+		t.Error("a heredoc without its closing line should fail")
+	}
+}
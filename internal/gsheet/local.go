@@ -0,0 +1,98 @@
+package gsheet
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/fzerorubigd/bgg-hotness/internal/snapshot"
+)
+
+// LocalBackend runs commands against a directory of CSV files, one per worksheet, in
+// the snapshot store's format. It is the spreadsheet for a run with no Google account:
+// hotness and aggregate can write to it and cleanup's archive can be read from it.
+type LocalBackend struct {
+	Store snapshot.Store
+}
+
+func (b LocalBackend) Worksheets(context.Context) ([]string, error) {
+	return b.Store.Titles()
+}
+
+func (b LocalBackend) AddWorksheet(_ context.Context, title string) error {
+	if b.Store.Exists(title) {
+		return fmt.Errorf("worksheet %q already exists", title)
+	}
+	return b.Store.Write(title, nil)
+}
+
+func (b LocalBackend) RemoveWorksheet(_ context.Context, title string) error {
+	return b.Store.Remove(title)
+}
+
+func (b LocalBackend) Update(_ context.Context, r Range, data [][]string) error {
+	rows, err := b.Store.Read(r.Title)
+	if err != nil {
+		return err
+	}
+	for i, row := range data {
+		rows = setRow(rows, r.Row0+i, r.Col0, row)
+	}
+	return b.Store.Write(r.Title, rows)
+}
+
+func (b LocalBackend) Append(_ context.Context, title string, col int, data [][]string) error {
+	rows, err := b.Store.Read(title)
+	if err != nil {
+		return err
+	}
+	next := len(trimRows(rows))
+	for i, row := range data {
+		rows = setRow(rows, next+i, col, row)
+	}
+	return b.Store.Write(title, rows)
+}
+
+func (b LocalBackend) Get(_ context.Context, r Range) ([][]string, error) {
+	rows, err := b.Store.Read(r.Title)
+	if err != nil {
+		return nil, err
+	}
+	var out [][]string
+	for i := r.Row0; i < len(rows) && (r.Row1 < 0 || i <= r.Row1); i++ {
+		var row []string
+		for j := r.Col0; j < len(rows[i]) && (r.Col1 < 0 || j <= r.Col1); j++ {
+			row = append(row, rows[i][j])
+		}
+		out = append(out, row)
+	}
+	return trimRows(out), nil
+}
+
+// setRow writes values into rows at row, col, growing rows as needed.
+func setRow(rows [][]string, row, col int, values []string) [][]string {
+	for len(rows) <= row {
+		rows = append(rows, nil)
+	}
+	for len(rows[row]) < col+len(values) {
+		rows[row] = append(rows[row], "")
+	}
+	copy(rows[row][col:], values)
+	return rows
+}
+
+// trimRows drops trailing empty cells of each row and then trailing empty rows, the
+// shape Sheets returns a read in.
+func trimRows(rows [][]string) [][]string {
+	for i, row := range rows {
+		n := len(row)
+		for n > 0 && row[n-1] == "" {
+			n--
+		}
+		rows[i] = row[:n]
+	}
+	n := len(rows)
+	for n > 0 && len(rows[n-1]) == 0 {
+		n--
+	}
+	return rows[:n]
+}
@@ -0,0 +1,164 @@
+package gsheet
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Range is a parsed A1 range. Rows and columns are zero-based and inclusive; an end of
+// -1 is unbounded, as in "A1:E" (every row) or a bare worksheet title.
+type Range struct {
+	Title      string
+	Row0, Col0 int
+	Row1, Col1 int
+}
+
+// Rows returns the number of rows the range spans, or -1 when it is unbounded.
+func (r Range) Rows() int {
+	if r.Row1 < 0 {
+		return -1
+	}
+	return r.Row1 - r.Row0 + 1
+}
+
+// Cols returns the number of columns the range spans, or -1 when it is unbounded.
+func (r Range) Cols() int {
+	if r.Col1 < 0 {
+		return -1
+	}
+	return r.Col1 - r.Col0 + 1
+}
+
+// maxCol is the last column a worksheet can have (ZZZ), the end String writes for a
+// range that is unbounded both ways from a cell other than A1; A1 notation has no
+// "B1 onwards" form.
+const maxCol = 18277
+
+// String formats the range back into A1 notation, quoting the title.
+func (r Range) String() string {
+	s := QuoteTitle(r.Title)
+	if r.Row1 < 0 && r.Col1 < 0 {
+		if r.Row0 == 0 && r.Col0 == 0 {
+			return s
+		}
+		r.Col1 = maxCol
+	}
+	s += "!" + cellName(r.Row0, r.Col0)
+	if r.Row1 == r.Row0 && r.Col1 == r.Col0 {
+		return s
+	}
+	return s + ":" + cellName(r.Row1, r.Col1)
+}
+
+// QuoteTitle quotes a worksheet title for use in a range, so titles with spaces or
+// punctuation (Monthly - 2026-3) are addressed correctly.
+func QuoteTitle(title string) string {
+	return "'" + strings.ReplaceAll(title, "'", "''") + "'"
+}
+
+func cellName(row, col int) string {
+	var s string
+	if col >= 0 {
+		for c := col + 1; c > 0; c = (c - 1) / 26 {
+			s = string(rune('A'+(c-1)%26)) + s
+		}
+	}
+	if row >= 0 {
+		s += strconv.Itoa(row + 1)
+	}
+	return s
+}
+
+// ParseRange parses the subset of A1 notation the commands produce: Title, Title!A1,
+// Title!A1:E51, Title!A1:E and Title!A:E, with the title optionally single-quoted
+// ('Monthly - 2026-3', a literal quote doubled).
+func ParseRange(s string) (Range, error) {
+	title, cells := s, ""
+	if strings.HasPrefix(s, "'") {
+		end := -1
+		for i := 1; i < len(s); i++ {
+			if s[i] == '\'' {
+				if i+1 < len(s) && s[i+1] == '\'' {
+					i++
+					continue
+				}
+				end = i
+				break
+			}
+		}
+		if end < 0 {
+			return Range{}, fmt.Errorf("unterminated quoted title in range %q", s)
+		}
+		title = strings.ReplaceAll(s[1:end], "''", "'")
+		rest := s[end+1:]
+		if rest != "" {
+			var ok bool
+			if cells, ok = strings.CutPrefix(rest, "!"); !ok {
+				return Range{}, fmt.Errorf("unable to parse range %q", s)
+			}
+		}
+	} else if i := strings.LastIndex(s, "!"); i >= 0 {
+		title, cells = s[:i], s[i+1:]
+	}
+	if title == "" {
+		return Range{}, fmt.Errorf("unable to parse range %q", s)
+	}
+
+	r := Range{Title: title, Row1: -1, Col1: -1}
+	if cells == "" {
+		return r, nil
+	}
+	from, to, isSpan := strings.Cut(cells, ":")
+	var err error
+	if r.Row0, r.Col0, err = parseCell(from); err != nil {
+		return Range{}, fmt.Errorf("unable to parse range %q: %w", s, err)
+	}
+	if r.Row0 < 0 {
+		r.Row0 = 0
+	}
+	if r.Col0 < 0 {
+		r.Col0 = 0
+	}
+	if !isSpan {
+		r.Row1, r.Col1 = r.Row0, r.Col0
+		return r, nil
+	}
+	if r.Row1, r.Col1, err = parseCell(to); err != nil {
+		return Range{}, fmt.Errorf("unable to parse range %q: %w", s, err)
+	}
+	if (r.Row1 >= 0 && r.Row1 < r.Row0) || (r.Col1 >= 0 && r.Col1 < r.Col0) {
+		return Range{}, fmt.Errorf("range %q ends before it starts", s)
+	}
+	return r, nil
+}
+
+// parseCell parses "E51", "E" or "51" into zero-based row and column, -1 for a part
+// that is absent.
+func parseCell(s string) (row, col int, err error) {
+	i := 0
+	for i < len(s) && s[i] >= 'A' && s[i] <= 'Z' {
+		i++
+	}
+	letters, digits := s[:i], s[i:]
+	if letters == "" && digits == "" {
+		return 0, 0, fmt.Errorf("empty cell reference")
+	}
+	col = -1
+	if letters != "" {
+		col = 0
+		for _, c := range letters {
+			col = col*26 + int(c-'A'+1)
+		}
+		col--
+	}
+	row = -1
+	if digits != "" {
+		n, err := strconv.Atoi(digits)
+		if err != nil || n < 1 {
+			return 0, 0, fmt.Errorf("bad row in %q", s)
+		}
+		row = n - 1
+	}
+	return row, col, nil
+}
@@ -0,0 +1,38 @@
+package gsheet
+
+import "testing"
+
+func TestParseRange(t *testing.T) {
+	cases := map[string]Range{
+		"Aggregate":                {Title: "Aggregate", Row1: -1, Col1: -1},
+		"Aggregate!A1":             {Title: "Aggregate", Row1: 0, Col1: 0},
+		"2026-08-01!A1:E51":        {Title: "2026-08-01", Row1: 50, Col1: 4},
+		"'Monthly - 2026-3'!A1:E":  {Title: "Monthly - 2026-3", Row1: -1, Col1: 4},
+		"'it''s'!B2:C":             {Title: "it's", Row0: 1, Col0: 1, Row1: -1, Col1: 2},
+		"2026-08-01_14-days!A:AA9": {Title: "2026-08-01_14-days", Row1: 8, Col1: 26},
+	}
+	for in, want := range cases {
+		got, err := ParseRange(in)
+		if err != nil {
+			t.Errorf("ParseRange(%q): %v", in, err)
+			continue
+		}
+		if got != want {
+			t.Errorf("ParseRange(%q) = %+v, want %+v", in, got, want)
+		}
+		// String is what the backends send; it must parse back to the same range.
+		if again, err := ParseRange(got.String()); err != nil || again != got {
+			t.Errorf("ParseRange(%q.String() = %q) = %+v, %v", in, got.String(), again, err)
+		}
+	}
+	if _, err := ParseRange("'unterminated!A1"); err == nil {
+		t.Error("an unterminated quoted title should fail")
+	}
+}
+
+func TestRangeStringFromCell(t *testing.T) {
+	r := Range{Title: "Aggregate", Col0: 1, Row1: -1, Col1: -1}
+	if got, want := r.String(), "'Aggregate'!B1:ZZZ"; got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+}
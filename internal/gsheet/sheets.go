@@ -0,0 +1,92 @@
+package gsheet
+
+import (
+	"context"
+	"fmt"
+
+	"google.golang.org/api/sheets/v4"
+)
+
+// SheetsBackend runs commands against a spreadsheet through the Sheets API.
+type SheetsBackend struct {
+	Service       *sheets.Service
+	SpreadsheetID string
+}
+
+func (b SheetsBackend) Worksheets(ctx context.Context) ([]string, error) {
+	sp, err := b.Service.Spreadsheets.Get(b.SpreadsheetID).Context(ctx).Do()
+	if err != nil {
+		return nil, err
+	}
+	titles := make([]string, 0, len(sp.Sheets))
+	for _, sh := range sp.Sheets {
+		titles = append(titles, sh.Properties.Title)
+	}
+	return titles, nil
+}
+
+func (b SheetsBackend) AddWorksheet(ctx context.Context, title string) error {
+	_, err := b.Service.Spreadsheets.BatchUpdate(b.SpreadsheetID, &sheets.BatchUpdateSpreadsheetRequest{
+		Requests: []*sheets.Request{{AddSheet: &sheets.AddSheetRequest{Properties: &sheets.SheetProperties{Title: title}}}},
+	}).Context(ctx).Do()
+	return err
+}
+
+// RemoveWorksheet looks the title up first, since deleteSheet takes the sheet id.
+func (b SheetsBackend) RemoveWorksheet(ctx context.Context, title string) error {
+	sp, err := b.Service.Spreadsheets.Get(b.SpreadsheetID).Context(ctx).Do()
+	if err != nil {
+		return err
+	}
+	for _, sh := range sp.Sheets {
+		if sh.Properties.Title != title {
+			continue
+		}
+		_, err := b.Service.Spreadsheets.BatchUpdate(b.SpreadsheetID, &sheets.BatchUpdateSpreadsheetRequest{
+			Requests: []*sheets.Request{{DeleteSheet: &sheets.DeleteSheetRequest{SheetId: sh.Properties.SheetId}}},
+		}).Context(ctx).Do()
+		return err
+	}
+	return fmt.Errorf("no worksheet %q", title)
+}
+
+// Update writes with USER_ENTERED, as the action does, so "12" lands as a number and
+// sorts and exports the same as the sheets the action wrote.
+func (b SheetsBackend) Update(ctx context.Context, r Range, data [][]string) error {
+	_, err := b.Service.Spreadsheets.Values.Update(b.SpreadsheetID, r.String(), &sheets.ValueRange{Values: values(data)}).
+		ValueInputOption("USER_ENTERED").Context(ctx).Do()
+	return err
+}
+
+func (b SheetsBackend) Append(ctx context.Context, title string, col int, data [][]string) error {
+	r := Range{Title: title, Col0: col, Row1: -1, Col1: -1}
+	_, err := b.Service.Spreadsheets.Values.Append(b.SpreadsheetID, r.String(), &sheets.ValueRange{Values: values(data)}).
+		ValueInputOption("USER_ENTERED").InsertDataOption("INSERT_ROWS").Context(ctx).Do()
+	return err
+}
+
+func (b SheetsBackend) Get(ctx context.Context, r Range) ([][]string, error) {
+	vr, err := b.Service.Spreadsheets.Values.Get(b.SpreadsheetID, r.String()).Context(ctx).Do()
+	if err != nil {
+		return nil, err
+	}
+	rows := make([][]string, len(vr.Values))
+	for i := range vr.Values {
+		rows[i] = make([]string, len(vr.Values[i]))
+		for j := range vr.Values[i] {
+			rows[i][j] = fmt.Sprint(vr.Values[i][j])
+		}
+	}
+	return rows, nil
+}
+
+func values(data [][]string) [][]interface{} {
+	out := make([][]interface{}, len(data))
+	for i, row := range data {
+		out[i] = make([]interface{}, len(row))
+		for j, v := range row {
+			out[i][j] = v
+		}
+	}
+	return out
+}
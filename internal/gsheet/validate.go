@@ -0,0 +1,112 @@
+package gsheet
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Validate checks a list against the worksheets that exist before it runs, replaying
+// the adds and removes in order. It reports every problem, not just the first:
+//
+//   - a command without a worksheet title, or with a negative MinCol;
+//   - addWorksheet of a title that exists, any other command on one that does not;
+//   - a range that does not parse or names a different worksheet than the command;
+//   - updateData whose data is not the shape of its range in a bounded dimension, and
+//     updateData or appendData with no rows.
+//
+// These are the mistakes the action only reports halfway through a run, after the
+// commands before the bad one have already changed the sheet.
+func Validate(l List, existing []string) error {
+	titles := make(map[string]bool, len(existing))
+	for _, t := range existing {
+		titles[t] = true
+	}
+
+	var errs []error
+	for i, c := range l {
+		if err := validate(c, titles); err != nil {
+			errs = append(errs, fmt.Errorf("command %d (%s %q): %w", i, c.Name(), c.Worksheet(), err))
+		}
+		// Track the sheet as the action would leave it even after an invalid command, so
+		// one mistake is not reported again by every command after it.
+		switch c.(type) {
+		case AddWorksheet:
+			titles[c.Worksheet()] = true
+		case RemoveWorksheet:
+			delete(titles, c.Worksheet())
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func validate(c Command, titles map[string]bool) error {
+	title := c.Worksheet()
+	if title == "" {
+		return errors.New("no worksheet title")
+	}
+	if _, ok := c.(AddWorksheet); ok {
+		if titles[title] {
+			return errors.New("worksheet already exists")
+		}
+		return nil
+	}
+	if !titles[title] {
+		return errors.New("worksheet does not exist")
+	}
+
+	switch c := c.(type) {
+	case UpdateData:
+		if len(c.Data) == 0 {
+			return errors.New("no data")
+		}
+		r, err := commandRange(c.Range, title, c.MinCol)
+		if err != nil {
+			return err
+		}
+		if n := r.Rows(); n >= 0 && n != len(c.Data) {
+			return fmt.Errorf("range %s has %d rows, data has %d", c.Range, n, len(c.Data))
+		}
+		if n := r.Cols(); n >= 0 {
+			for j, row := range c.Data {
+				if len(row) != n {
+					return fmt.Errorf("range %s has %d columns, data row %d has %d", c.Range, n, j, len(row))
+				}
+			}
+		}
+	case AppendData:
+		if len(c.Data) == 0 {
+			return errors.New("no data")
+		}
+		if c.MinCol < 0 {
+			return fmt.Errorf("minCol %d is negative", c.MinCol)
+		}
+	case GetData:
+		if _, err := commandRange(c.Range, title, c.MinCol); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// commandRange is the range a command addresses: Range when it is set, otherwise the
+// worksheet from column minCol (1-based, 0 meaning 1) of the first row.
+func commandRange(rng, title string, minCol int) (Range, error) {
+	if minCol < 0 {
+		return Range{}, fmt.Errorf("minCol %d is negative", minCol)
+	}
+	if rng == "" {
+		r := Range{Title: title, Row1: -1, Col1: -1}
+		if minCol > 0 {
+			r.Col0 = minCol - 1
+		}
+		return r, nil
+	}
+	r, err := ParseRange(rng)
+	if err != nil {
+		return Range{}, err
+	}
+	if r.Title != title {
+		return Range{}, fmt.Errorf("range %s is on worksheet %q", rng, r.Title)
+	}
+	return r, nil
+}
@@ -0,0 +1,67 @@
+package gsheet
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidateDaily(t *testing.T) {
+	if err := Validate(daily(), []string{"Aggregate"}); err != nil {
+		t.Errorf("Validate(daily) = %v", err)
+	}
+}
+
+func TestValidateReportsEachProblem(t *testing.T) {
+	rows := [][]string{{"Rank", "BGGID"}, {"1", "174430"}}
+	cases := map[string]struct {
+		list List
+		want string
+	}{
+		"update before add": {
+			List{UpdateData{Data: rows, Range: "2026-08-01!A1:B2", WorksheetTitle: "2026-08-01"}},
+			"worksheet does not exist",
+		},
+		"add twice": {
+			List{AddWorksheet{WorksheetTitle: "Aggregate"}},
+			"already exists",
+		},
+		"removed then read": {
+			List{RemoveWorksheet{WorksheetTitle: "Aggregate"}, GetData{Range: "Aggregate!A1", WorksheetTitle: "Aggregate"}},
+			"command 1 (getData",
+		},
+		"too few rows in range": {
+			List{UpdateData{Data: rows, Range: "Aggregate!A1:B1", WorksheetTitle: "Aggregate"}},
+			"has 1 rows, data has 2",
+		},
+		"too many columns in range": {
+			List{UpdateData{Data: rows, Range: "Aggregate!A1:E2", WorksheetTitle: "Aggregate"}},
+			"has 5 columns, data row 0 has 2",
+		},
+		"range on another worksheet": {
+			List{GetData{Range: "2026-08-01!A1", WorksheetTitle: "Aggregate"}},
+			`is on worksheet "2026-08-01"`,
+		},
+		"no title": {
+			List{AppendData{Data: rows}},
+			"no worksheet title",
+		},
+		"no data": {
+			List{AppendData{WorksheetTitle: "Aggregate"}},
+			"no data",
+		},
+	}
+	for name, c := range cases {
+		err := Validate(c.list, []string{"Aggregate"})
+		if err == nil || !strings.Contains(err.Error(), c.want) {
+			t.Errorf("%s: Validate = %v, want an error containing %q", name, err, c.want)
+		}
+	}
+}
+
+// An open-ended range ("A1:E") accepts any number of rows, the form cleanup reads with.
+func TestValidateOpenRange(t *testing.T) {
+	l := List{UpdateData{Data: [][]string{{"a", "b"}, {"c", "d"}}, Range: "Aggregate!A1:B", WorksheetTitle: "Aggregate"}}
+	if err := Validate(l, []string{"Aggregate"}); err != nil {
+		t.Errorf("Validate = %v", err)
+	}
+}
@@ -0,0 +1,279 @@
+// Package history reads the daily hot lists back out of the snapshot store: the days
+// there are, the list on a day, and one game's rank over a range of days. It is the
+// read side of what fetch and cleanup write, and what serve, mcp, site and shownotes
+// query instead of the spreadsheet.
+//
+// A daily snapshot is the worksheet hotness wrote, titled with its date (2026-08-01)
+// and holding a Rank, BGGID, Change, Link, Name header and one row per game. Other
+// snapshots in the store (the rolling and period aggregates cleanup also archives) are
+// ignored.
+package history
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/fzerorubigd/bgg-hotness/internal/snapshot"
+)
+
+// Entry is one game on one day's hot list.
+type Entry struct {
+	Rank   int
+	ID     int64
+	Change string // As BGG reports it, e.g. "3" or "-2"; empty on an aggregate.
+	Name   string
+}
+
+// Day is one day's hot list, in rank order.
+type Day struct {
+	Date    time.Time
+	Entries []Entry
+}
+
+// ErrNoDay is returned for a date the store has no list for.
+var ErrNoDay = errors.New("history: no list for that day")
+
+// History is the daily lists in a snapshot store.
+type History struct {
+	Store snapshot.Store
+}
+
+// Dates returns the dates the store has a list for, oldest first.
+func (h History) Dates() ([]time.Time, error) {
+	titles, err := h.Store.Titles()
+	if err != nil {
+		return nil, err
+	}
+	var dates []time.Time
+	for _, t := range titles {
+		if d, err := time.Parse(time.DateOnly, t); err == nil {
+			dates = append(dates, d)
+		}
+	}
+	sort.Slice(dates, func(i, j int) bool { return dates[i].Before(dates[j]) })
+	return dates, nil
+}
+
+// Latest returns the most recent date in the store, or ErrNoDay when it is empty.
+func (h History) Latest() (time.Time, error) {
+	dates, err := h.Dates()
+	if err != nil {
+		return time.Time{}, err
+	}
+	if len(dates) == 0 {
+		return time.Time{}, ErrNoDay
+	}
+	return dates[len(dates)-1], nil
+}
+
+// Day returns the list for date, or an error wrapping ErrNoDay.
+func (h History) Day(date time.Time) (Day, error) {
+	title := date.Format(time.DateOnly)
+	rows, err := h.Store.Read(title)
+	if errors.Is(err, os.ErrNotExist) {
+		return Day{}, fmt.Errorf("%w: %s", ErrNoDay, title)
+	}
+	if err != nil {
+		return Day{}, err
+	}
+	return parseDay(date, rows)
+}
+
+// parseDay reads a daily worksheet. Columns are found by header name, so a sheet with
+// the columns in another order, or the Wins column of an aggregate, still reads.
+func parseDay(date time.Time, rows [][]string) (Day, error) {
+	d := Day{Date: date}
+	if len(rows) == 0 {
+		return d, nil
+	}
+	col := map[string]int{}
+	for i, h := range rows[0] {
+		col[h] = i
+	}
+	idCol, ok := col["BGGID"]
+	if !ok {
+		return Day{}, fmt.Errorf("history: %s has no BGGID column", date.Format(time.DateOnly))
+	}
+	cell := func(row []string, name string) string {
+		if i, ok := col[name]; ok && i < len(row) {
+			return row[i]
+		}
+		return ""
+	}
+	for i, row := range rows[1:] {
+		if idCol >= len(row) {
+			continue
+		}
+		id, err := strconv.ParseInt(row[idCol], 10, 64)
+		if err != nil {
+			continue
+		}
+		rank, err := strconv.Atoi(cell(row, "Rank"))
+		if err != nil {
+			rank = i + 1
+		}
+		d.Entries = append(d.Entries, Entry{Rank: rank, ID: id, Change: cell(row, "Change"), Name: cell(row, "Name")})
+	}
+	sort.SliceStable(d.Entries, func(i, j int) bool { return d.Entries[i].Rank < d.Entries[j].Rank })
+	return d, nil
+}
+
+// Range returns the lists for every stored day in [from, to], oldest first. A zero
+// from or to leaves that end open.
+func (h History) Range(from, to time.Time) ([]Day, error) {
+	dates, err := h.Dates()
+	if err != nil {
+		return nil, err
+	}
+	var days []Day
+	for _, date := range dates {
+		if (!from.IsZero() && date.Before(from)) || (!to.IsZero() && date.After(to)) {
+			continue
+		}
+		d, err := h.Day(date)
+		if err != nil {
+			return nil, err
+		}
+		days = append(days, d)
+	}
+	return days, nil
+}
+
+// Ballots returns days as the ballots aggregate ranks: one [date, id1, id2, ...] row
+// per day, ids in rank order, the shape of a row on the Aggregate worksheet.
+func Ballots(days []Day) [][]string {
+	ballots := make([][]string, 0, len(days))
+	for _, d := range days {
+		b := make([]string, 0, len(d.Entries)+1)
+		b = append(b, d.Date.Format(time.DateOnly))
+		for _, e := range d.Entries {
+			b = append(b, strconv.FormatInt(e.ID, 10))
+		}
+		ballots = append(ballots, b)
+	}
+	return ballots
+}
+
+// FromBallots is the inverse of Ballots, for lists read from the Aggregate worksheet:
+// each id's rank is its position on the ballot. A ballot whose date does not parse is
+// skipped.
+func FromBallots(ballots [][]string) []Day {
+	days := make([]Day, 0, len(ballots))
+	for _, b := range ballots {
+		if len(b) == 0 {
+			continue
+		}
+		date, err := time.Parse(time.DateOnly, b[0])
+		if err != nil {
+			continue
+		}
+		d := Day{Date: date}
+		for i, v := range b[1:] {
+			if id, err := strconv.ParseInt(v, 10, 64); err == nil {
+				d.Entries = append(d.Entries, Entry{Rank: i + 1, ID: id})
+			}
+		}
+		days = append(days, d)
+	}
+	return days
+}
+
+// Names returns the most recent name each game had across days.
+func Names(days []Day) map[int64]string {
+	names := map[int64]string{}
+	for _, d := range days {
+		for _, e := range d.Entries {
+			if e.Name != "" {
+				names[e.ID] = e.Name
+			}
+		}
+	}
+	return names
+}
+
+// Point is a game's rank on one day; Rank is 0 on a day it was not on the list.
+type Point struct {
+	Date time.Time
+	Rank int
+}
+
+// Game returns id's rank on each of days, in order.
+func Game(days []Day, id int64) []Point {
+	points := make([]Point, len(days))
+	for i, d := range days {
+		points[i].Date = d.Date
+		for _, e := range d.Entries {
+			if e.ID == id {
+				points[i].Rank = e.Rank
+				break
+			}
+		}
+	}
+	return points
+}
+
+// IDs returns every game on any of days, in order of first appearance.
+func IDs(days []Day) []int64 {
+	seen := map[int64]bool{}
+	var ids []int64
+	for _, d := range days {
+		for _, e := range d.Entries {
+			if !seen[e.ID] {
+				seen[e.ID] = true
+				ids = append(ids, e.ID)
+			}
+		}
+	}
+	return ids
+}
+
+// Stats is how a game did over a run of days.
+type Stats struct {
+	// Days is the number of days it was on the list, out of Total.
+	Days, Total int
+	// Best is its best rank, Mean its mean rank over the days it was on the list.
+	Best int
+	Mean float64
+	// Latest is its rank on the last day, 0 when it was not on the list that day.
+	Latest int
+	// CurrentStreak is the run of consecutive days on the list that ends on the last
+	// day, 0 when it was not on the list that day; LongestStreak the longest run.
+	CurrentStreak, LongestStreak int
+	// First and Last are the first and last day it was on the list, zero if never.
+	First, Last time.Time
+}
+
+// Summarize computes Stats from a game's points, in date order. A streak counts
+// consecutive stored days, so a day missing from the store does not break one.
+func Summarize(points []Point) Stats {
+	s := Stats{Total: len(points)}
+	sum, run := 0, 0
+	for _, p := range points {
+		if p.Rank == 0 {
+			run = 0
+			continue
+		}
+		if s.Days == 0 {
+			s.First = p.Date
+			s.Best = p.Rank
+		}
+		s.Days++
+		s.Last = p.Date
+		s.Best = min(s.Best, p.Rank)
+		sum += p.Rank
+		run++
+		s.LongestStreak = max(s.LongestStreak, run)
+	}
+	s.CurrentStreak = run
+	if s.Days > 0 {
+		s.Mean = float64(sum) / float64(s.Days)
+	}
+	if len(points) > 0 {
+		s.Latest = points[len(points)-1].Rank
+	}
+	return s
+}
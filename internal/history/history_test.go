@@ -0,0 +1,128 @@
+package history
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/fzerorubigd/bgg-hotness/internal/snapshot"
+)
+
+func day(d int) time.Time { return time.Date(2026, 8, d, 0, 0, 0, 0, time.UTC) }
+
+func testHistory(t *testing.T) History {
+	t.Helper()
+	s := snapshot.Store{Dir: t.TempDir()}
+	sheets := map[string][][]string{
+		"2026-08-01": {
+			{"Rank", "BGGID", "Change", "Link", "Name"},
+			{"1", "174430", "0", "https://boardgamegeek.com/boardgame/174430/", "Gloomhaven"},
+			{"2", "224517", "3", "https://boardgamegeek.com/boardgame/224517/", "Brass: Birmingham"},
+		},
+		"2026-08-02": {
+			{"Rank", "BGGID", "Change", "Link", "Name"},
+			{"1", "224517", "1", "", "Brass: Birmingham"},
+			{"2", "342942", "", "", "Ark Nova"},
+		},
+		"2026-08-03": {
+			// Columns out of order, as an edited sheet might have them.
+			{"Name", "BGGID", "Rank"},
+			{"Ark Nova", "342942", "2"},
+			{"Brass: Birmingham", "224517", "1"},
+		},
+		// Not a daily list; Dates must skip it.
+		"2026-08-03_7-days": {{"Rank", "BGGID", "Wins", "Link", "Name"}, {"1", "224517", "12", "", ""}},
+	}
+	for title, rows := range sheets {
+		if err := s.Write(title, rows); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return History{Store: s}
+}
+
+func TestDatesAndDay(t *testing.T) {
+	h := testHistory(t)
+	dates, err := h.Dates()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []time.Time{day(1), day(2), day(3)}; !reflect.DeepEqual(dates, want) {
+		t.Errorf("Dates = %v, want %v", dates, want)
+	}
+	if latest, err := h.Latest(); err != nil || !latest.Equal(day(3)) {
+		t.Errorf("Latest = %v, %v; want %v", latest, err, day(3))
+	}
+
+	d, err := h.Day(day(3))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Entry{{Rank: 1, ID: 224517, Name: "Brass: Birmingham"}, {Rank: 2, ID: 342942, Name: "Ark Nova"}}
+	if !reflect.DeepEqual(d.Entries, want) {
+		t.Errorf("Day entries = %+v, want %+v", d.Entries, want)
+	}
+
+	if _, err := h.Day(day(9)); !errors.Is(err, ErrNoDay) {
+		t.Errorf("Day(missing) error = %v, want ErrNoDay", err)
+	}
+	if _, err := (History{Store: snapshot.Store{Dir: t.TempDir()}}).Latest(); !errors.Is(err, ErrNoDay) {
+		t.Errorf("Latest on an empty store error = %v, want ErrNoDay", err)
+	}
+}
+
+func TestRangeGameAndBallots(t *testing.T) {
+	h := testHistory(t)
+	days, err := h.Range(day(2), time.Time{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(days) != 2 || !days[0].Date.Equal(day(2)) {
+		t.Fatalf("Range(2nd, open) = %d day(s) from %v, want 2 from %v", len(days), days[0].Date, day(2))
+	}
+
+	all, err := h.Range(time.Time{}, time.Time{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Point{{day(1), 0}, {day(2), 2}, {day(3), 2}}
+	if got := Game(all, 342942); !reflect.DeepEqual(got, want) {
+		t.Errorf("Game = %v, want %v", got, want)
+	}
+	if got := Names(all)[224517]; got != "Brass: Birmingham" {
+		t.Errorf("Names[224517] = %q", got)
+	}
+	if got, want := IDs(all), []int64{174430, 224517, 342942}; !reflect.DeepEqual(got, want) {
+		t.Errorf("IDs = %v, want %v", got, want)
+	}
+
+	ballots := Ballots(all)
+	if want := []string{"2026-08-01", "174430", "224517"}; !reflect.DeepEqual(ballots[0], want) {
+		t.Errorf("Ballots[0] = %v, want %v", ballots[0], want)
+	}
+	back := FromBallots(ballots)
+	if got, want := Game(back, 224517), Game(all, 224517); !reflect.DeepEqual(got, want) {
+		t.Errorf("Game after FromBallots = %v, want %v", got, want)
+	}
+}
+
+func TestSummarize(t *testing.T) {
+	points := []Point{{day(1), 3}, {day(2), 1}, {day(3), 0}, {day(4), 2}, {day(5), 4}, {day(6), 6}}
+	want := Stats{
+		Days: 5, Total: 6, Best: 1, Mean: 16.0 / 5, Latest: 6,
+		CurrentStreak: 3, LongestStreak: 3, First: day(1), Last: day(6),
+	}
+	if got := Summarize(points); !reflect.DeepEqual(got, want) {
+		t.Errorf("Summarize = %+v, want %+v", got, want)
+	}
+
+	points[5].Rank = 0
+	got := Summarize(points)
+	if got.CurrentStreak != 0 || got.LongestStreak != 2 || got.Latest != 0 || !got.Last.Equal(day(5)) {
+		t.Errorf("Summarize off the list on the last day = %+v", got)
+	}
+	if got := Summarize(nil); !reflect.DeepEqual(got, Stats{}) {
+		t.Errorf("Summarize(nil) = %+v, want zero", got)
+	}
+}
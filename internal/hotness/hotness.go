@@ -0,0 +1,109 @@
+// Package hotness is the fetch subcommand: it records the day's BGG hot list.
+package hotness
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/fzerorubigd/bggo"
+	"go.uber.org/ratelimit"
+
+	"github.com/fzerorubigd/bgg-hotness/internal/bgg"
+	"github.com/fzerorubigd/bgg-hotness/internal/cli"
+	"github.com/fzerorubigd/bgg-hotness/internal/config"
+	"github.com/fzerorubigd/bgg-hotness/internal/gsheet"
+	"github.com/fzerorubigd/bgg-hotness/internal/output"
+	"github.com/fzerorubigd/bgg-hotness/internal/snapshot"
+)
+
+// Run fetches today's hot list and writes the commands that record it: a worksheet
+// for the day and a ballot row on Aggregate. With -archive-dir it also keeps the day's
+// list in the snapshot store, where serve and the other readers of history find it
+// without waiting for cleanup to archive the worksheet.
+func Run(ctx context.Context, cfg config.Config, args []string) error {
+	fs := flag.NewFlagSet("fetch", flag.ContinueOnError)
+	var bggEndpoint, out, archiveDir string
+	fs.StringVar(&bggEndpoint, "bgg-endpoint", cfg.BGGEndpoint, "Base URL to send BGG API requests to instead of boardgamegeek.com, e.g. a fixture server")
+	fs.StringVar(&archiveDir, "archive-dir", cfg.ArchiveDir, "Directory to also store the day's list in (as CSV), empty to disable")
+	fs.StringVar(&out, "output", output.Default(), "Where to write the result, KIND or KIND:FILE with KIND one of "+output.Kinds)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	sink, err := output.New(out, os.Stdout)
+	if err != nil {
+		return err
+	}
+
+	rl := ratelimit.New(1, ratelimit.Per(time.Second)) // 1 request per second.
+	token, err := cli.BGGToken()
+	if err != nil {
+		return err
+	}
+	c, err := bgg.NewClient(token, bggEndpoint, bggo.WithLimiter(rl))
+	if err != nil {
+		return err
+	}
+	hot, err := c.GetHotness(ctx, bggo.GetHotnessRequest{Count: 50})
+	if err != nil {
+		return err
+	}
+
+	// bggo's HotnessItem carries Name inline, so the name column is filled here
+	// directly — no second batched name lookup, and no positional-index hazard.
+	data := make([][]string, len(hot))
+	aggregate := make([]string, len(hot))
+	for i := range hot {
+		aggregate[i] = fmt.Sprint(hot[i].ID)
+		data[i] = append(data[i],
+			fmt.Sprint(i+1),
+			fmt.Sprint(hot[i].ID),
+			fmt.Sprint(hot[i].Delta),
+			fmt.Sprintf("https://boardgamegeek.com/boardgame/%d/", hot[i].ID),
+			hot[i].Name,
+		)
+	}
+
+	base := []string{
+		"Rank",
+		"BGGID",
+		"Change",
+		"Link",
+		"Name",
+	}
+	data = append([][]string{base}, data...)
+
+	today := time.Now().Format(time.DateOnly)
+	aggregate = append([]string{today}, aggregate...)
+	if archiveDir != "" {
+		if err := (snapshot.Store{Dir: archiveDir}).Write(today, data); err != nil {
+			return err
+		}
+	}
+	return sink.Write(output.Result{
+		Title:    today,
+		Rows:     data,
+		Commands: dailyCommands(today, data, aggregate),
+	})
+}
+
+// dailyCommands creates today's worksheet with the ranked rows and appends the ids, as
+// one ballot, to the Aggregate worksheet aggregate reads from.
+func dailyCommands(today string, data [][]string, aggregate []string) gsheet.List {
+	return gsheet.List{
+		gsheet.AddWorksheet{WorksheetTitle: today},
+		gsheet.UpdateData{
+			Data:           data,
+			MinCol:         1,
+			Range:          fmt.Sprintf("%s!A1:E%d", today, len(data)),
+			WorksheetTitle: today,
+		},
+		gsheet.AppendData{
+			Data:           [][]string{aggregate},
+			MinCol:         1,
+			WorksheetTitle: "Aggregate",
+		},
+	}
+}
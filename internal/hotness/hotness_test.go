@@ -0,0 +1,23 @@
+package hotness
+
+import (
+	"testing"
+
+	"github.com/fzerorubigd/bgg-hotness/internal/gsheet"
+)
+
+// The daily list must be valid against a spreadsheet that only has Aggregate, the state
+// every run starts from, for any number of games the hot list returns.
+func TestDailyCommandsValid(t *testing.T) {
+	for _, games := range []int{0, 1, 50} {
+		data := [][]string{{"Rank", "BGGID", "Change", "Link", "Name"}}
+		ids := []string{"2026-08-01"}
+		for i := 0; i < games; i++ {
+			data = append(data, []string{"1", "174430", "0", "https://boardgamegeek.com/boardgame/174430/", "Gloomhaven"})
+			ids = append(ids, "174430")
+		}
+		if err := gsheet.Validate(dailyCommands("2026-08-01", data, ids), []string{"Aggregate"}); err != nil {
+			t.Errorf("%d games: %v", games, err)
+		}
+	}
+}
@@ -0,0 +1,376 @@
+// Package mcpserver is the mcp subcommand: an MCP server, over stdio, with tools that
+// answer questions from the daily lists in the snapshot store. bggo's MCP server asks
+// BGG what is hot now; these tools ask our archive what was hot, so the show-prep
+// assistant can answer "what's been trending since the last episode" without the
+// spreadsheet:
+//
+//	hotness_on_date   the list on a day
+//	aggregate_range   the days of a range ranked into one list
+//	game_history      a game's rank on each day, with its streaks
+//	compare_periods   a range's ranking against the one before it: climbers, new, dropped
+//
+// Dates are YYYY-MM-DD. The rankings are aggregate.Rank, as the aggregate command and
+// serve compute them.
+package mcpserver
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/fzerorubigd/bgg-hotness/internal/aggregate"
+	"github.com/fzerorubigd/bgg-hotness/internal/config"
+	"github.com/fzerorubigd/bgg-hotness/internal/history"
+	"github.com/fzerorubigd/bgg-hotness/internal/snapshot"
+)
+
+// version is reported to clients in the initialize handshake.
+const version = "0.1.0"
+
+// defaultWindow is how many days a range covers when it gives no from.
+const defaultWindow = 14
+
+// Run serves the tools on stdin and stdout until the client disconnects or ctx is
+// cancelled.
+func Run(ctx context.Context, cfg config.Config, args []string) error {
+	fs := flag.NewFlagSet("mcp", flag.ContinueOnError)
+	var archiveDir string
+	fs.StringVar(&archiveDir, "archive-dir", cfg.ArchiveDir, "Directory of daily lists to answer from, as fetch -archive-dir and cleanup write it")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if archiveDir == "" {
+		return errors.New("-archive-dir (or archive_dir in the config) is required")
+	}
+	return NewServer(history.History{Store: snapshot.Store{Dir: archiveDir}}).Run(ctx, &mcp.StdioTransport{})
+}
+
+// Serve runs the tools over r and w, the stdio framing without the process's own
+// stdin and stdout, so a test can drive the server through a pipe.
+func Serve(ctx context.Context, h history.History, r io.ReadCloser, w io.WriteCloser) error {
+	return NewServer(h).Run(ctx, &mcp.IOTransport{Reader: r, Writer: w})
+}
+
+// NewServer returns an MCP server with the tools over h.
+func NewServer(h history.History) *mcp.Server {
+	t := tools{h: h}
+	s := mcp.NewServer(&mcp.Implementation{Name: "bgg-hotness", Version: version}, nil)
+	mcp.AddTool(s, &mcp.Tool{
+		Name:        "hotness_on_date",
+		Description: "The BGG hot list as recorded on one day, in rank order.",
+	}, t.hotnessOnDate)
+	mcp.AddTool(s, &mcp.Tool{
+		Name:        "aggregate_range",
+		Description: "Rank the daily hot lists of a date range into one list, each day counting as a ballot.",
+	}, t.aggregateRange)
+	mcp.AddTool(s, &mcp.Tool{
+		Name:        "game_history",
+		Description: "One game's rank on each recorded day of a range, with days on the list, best and mean rank and its current and longest streak.",
+	}, t.gameHistory)
+	mcp.AddTool(s, &mcp.Tool{
+		Name:        "compare_periods",
+		Description: "Compare the ranking of a date range with the ranking of an earlier one (by default the same number of days just before it): each game's move, the new entries and the games that dropped out.",
+	}, t.comparePeriods)
+	return s
+}
+
+type tools struct {
+	h history.History
+}
+
+type game struct {
+	Rank   int    `json:"rank"`
+	ID     int64  `json:"id"`
+	Name   string `json:"name"`
+	Change string `json:"change,omitempty" jsonschema:"the move BGG reported on that day"`
+	Score  int    `json:"score,omitempty" jsonschema:"the ranking method's score, higher is better"`
+}
+
+type dateInput struct {
+	Date string `json:"date" jsonschema:"YYYY-MM-DD, or latest for the most recent recorded day"`
+}
+
+type dayOutput struct {
+	Date  string `json:"date"`
+	Games []game `json:"games"`
+}
+
+func (t tools) hotnessOnDate(ctx context.Context, _ *mcp.CallToolRequest, in dateInput) (*mcp.CallToolResult, dayOutput, error) {
+	var (
+		date time.Time
+		err  error
+	)
+	if in.Date == "latest" || in.Date == "" {
+		date, err = t.h.Latest()
+	} else {
+		date, err = parseDate("date", in.Date)
+	}
+	if err != nil {
+		return nil, dayOutput{}, err
+	}
+	d, err := t.h.Day(date)
+	if err != nil {
+		return nil, dayOutput{}, err
+	}
+	out := dayOutput{Date: d.Date.Format(time.DateOnly), Games: []game{}}
+	for _, e := range d.Entries {
+		out.Games = append(out.Games, game{Rank: e.Rank, ID: e.ID, Name: e.Name, Change: e.Change})
+	}
+	return nil, out, nil
+}
+
+type rangeInput struct {
+	From   string `json:"from,omitempty" jsonschema:"first day, YYYY-MM-DD; default 14 days before to"`
+	To     string `json:"to,omitempty" jsonschema:"last day, YYYY-MM-DD; default the most recent recorded day"`
+	Method string `json:"method,omitempty" jsonschema:"schulze (default) or borda"`
+	Count  int    `json:"count,omitempty" jsonschema:"number of games to return, default 20"`
+}
+
+type rangeOutput struct {
+	From   string `json:"from"`
+	To     string `json:"to"`
+	Method string `json:"method"`
+	Days   int    `json:"days" jsonschema:"number of recorded days in the range"`
+	Games  []game `json:"games"`
+}
+
+func (t tools) aggregateRange(ctx context.Context, _ *mcp.CallToolRequest, in rangeInput) (*mcp.CallToolResult, rangeOutput, error) {
+	from, to, err := t.window(in.From, in.To)
+	if err != nil {
+		return nil, rangeOutput{}, err
+	}
+	r, err := t.rank(from, to, in.Method)
+	if err != nil {
+		return nil, rangeOutput{}, err
+	}
+	return nil, r.output(countOr(in.Count)), nil
+}
+
+type gameInput struct {
+	ID   int64  `json:"id" jsonschema:"the BGG id of the game"`
+	From string `json:"from,omitempty" jsonschema:"first day, YYYY-MM-DD; default the first recorded day"`
+	To   string `json:"to,omitempty" jsonschema:"last day, YYYY-MM-DD; default the most recent recorded day"`
+}
+
+type point struct {
+	Date string `json:"date"`
+	Rank int    `json:"rank" jsonschema:"0 when the game was not on the list that day"`
+}
+
+type gameOutput struct {
+	ID            int64   `json:"id"`
+	Name          string  `json:"name"`
+	Days          int     `json:"days" jsonschema:"days on the list"`
+	Total         int     `json:"total" jsonschema:"recorded days in the range"`
+	Best          int     `json:"best"`
+	Mean          float64 `json:"mean" jsonschema:"mean rank over the days on the list"`
+	Latest        int     `json:"latest" jsonschema:"rank on the last day, 0 when off the list"`
+	CurrentStreak int     `json:"current_streak" jsonschema:"consecutive days on the list ending on the last day"`
+	LongestStreak int     `json:"longest_streak"`
+	First         string  `json:"first" jsonschema:"first day on the list"`
+	Last          string  `json:"last" jsonschema:"last day on the list"`
+	History       []point `json:"history"`
+}
+
+func (t tools) gameHistory(ctx context.Context, _ *mcp.CallToolRequest, in gameInput) (*mcp.CallToolResult, gameOutput, error) {
+	from, to, err := parseRange(in.From, in.To)
+	if err != nil {
+		return nil, gameOutput{}, err
+	}
+	days, err := t.h.Range(from, to)
+	if err != nil {
+		return nil, gameOutput{}, err
+	}
+	points := history.Game(days, in.ID)
+	st := history.Summarize(points)
+	if st.Days == 0 {
+		return nil, gameOutput{}, fmt.Errorf("game %d was not on the hot list in that range", in.ID)
+	}
+	out := gameOutput{
+		ID:            in.ID,
+		Name:          history.Names(days)[in.ID],
+		Days:          st.Days,
+		Total:         st.Total,
+		Best:          st.Best,
+		Mean:          st.Mean,
+		Latest:        st.Latest,
+		CurrentStreak: st.CurrentStreak,
+		LongestStreak: st.LongestStreak,
+		First:         st.First.Format(time.DateOnly),
+		Last:          st.Last.Format(time.DateOnly),
+		History:       make([]point, len(points)),
+	}
+	for i, p := range points {
+		out.History[i] = point{Date: p.Date.Format(time.DateOnly), Rank: p.Rank}
+	}
+	return nil, out, nil
+}
+
+type compareInput struct {
+	From         string `json:"from,omitempty" jsonschema:"first day of the period, YYYY-MM-DD; default 14 days before to"`
+	To           string `json:"to,omitempty" jsonschema:"last day of the period, YYYY-MM-DD; default the most recent recorded day"`
+	PreviousFrom string `json:"previous_from,omitempty" jsonschema:"first day of the period to compare with; default as many days before previous_to as the period has"`
+	PreviousTo   string `json:"previous_to,omitempty" jsonschema:"last day of the period to compare with; default the day before from"`
+	Method       string `json:"method,omitempty" jsonschema:"schulze (default) or borda"`
+	Count        int    `json:"count,omitempty" jsonschema:"number of games to compare from the top of each ranking, default 20"`
+}
+
+type move struct {
+	Rank         int    `json:"rank"`
+	PreviousRank int    `json:"previous_rank" jsonschema:"rank in the previous period, 0 when it was not ranked at all"`
+	Change       int    `json:"change" jsonschema:"places climbed since the previous period, negative for a fall; 0 for a new entry"`
+	New          bool   `json:"new" jsonschema:"not in the previous period's top count"`
+	ID           int64  `json:"id"`
+	Name         string `json:"name"`
+}
+
+type compareOutput struct {
+	Period   rangeOutput `json:"period"`
+	Previous rangeOutput `json:"previous"`
+	Games    []move      `json:"games" jsonschema:"the period's top count with their moves"`
+	Dropped  []game      `json:"dropped" jsonschema:"games in the previous period's top count that are not in this one's, at their previous rank"`
+}
+
+func (t tools) comparePeriods(ctx context.Context, _ *mcp.CallToolRequest, in compareInput) (*mcp.CallToolResult, compareOutput, error) {
+	from, to, err := t.window(in.From, in.To)
+	if err != nil {
+		return nil, compareOutput{}, err
+	}
+	prevFrom, prevTo, err := parseRange(in.PreviousFrom, in.PreviousTo)
+	if err != nil {
+		return nil, compareOutput{}, err
+	}
+	if prevTo.IsZero() {
+		_, prevTo = aggregate.PreviousPeriod(from, to)
+	}
+	if prevFrom.IsZero() {
+		// As many days as the period, ending on prevTo, wherever prevTo is.
+		prevFrom = prevTo.AddDate(0, 0, -int(to.Sub(from).Hours()/24))
+	}
+	if prevFrom.After(prevTo) {
+		return nil, compareOutput{}, fmt.Errorf("previous_to %s is before previous_from %s", prevTo.Format(time.DateOnly), prevFrom.Format(time.DateOnly))
+	}
+
+	cur, err := t.rank(from, to, in.Method)
+	if err != nil {
+		return nil, compareOutput{}, err
+	}
+	prev, err := t.rank(prevFrom, prevTo, in.Method)
+	if err != nil {
+		return nil, compareOutput{}, err
+	}
+	count := countOr(in.Count)
+	cmp := aggregate.Compare(cur.placed, prev.placed, count)
+	out := compareOutput{
+		Period:   cur.output(count),
+		Previous: prev.output(count),
+		Games:    make([]move, len(cmp.Games)),
+		Dropped:  make([]game, len(cmp.Dropped)),
+	}
+	for i, m := range cmp.Games {
+		out.Games[i] = move{Rank: m.Rank, PreviousRank: m.PreviousRank, Change: m.Change, New: m.New, ID: m.ID, Name: m.Name}
+	}
+	for i, p := range cmp.Dropped {
+		out.Dropped[i] = gameOf(p)
+	}
+	return nil, out, nil
+}
+
+// ranking is the full ranking of a range, before it is cut to a count.
+type ranking struct {
+	from, to time.Time
+	method   string
+	days     int
+	placed   []aggregate.Placed
+}
+
+func (r ranking) output(count int) rangeOutput {
+	out := rangeOutput{
+		From:   r.from.Format(time.DateOnly),
+		To:     r.to.Format(time.DateOnly),
+		Method: r.method,
+		Days:   r.days,
+		Games:  []game{},
+	}
+	for _, p := range r.placed[:min(count, len(r.placed))] {
+		out.Games = append(out.Games, gameOf(p))
+	}
+	return out
+}
+
+func gameOf(p aggregate.Placed) game {
+	return game{Rank: p.Rank, ID: p.ID, Name: p.Name, Score: p.Score}
+}
+
+func (t tools) rank(from, to time.Time, method string) (ranking, error) {
+	if method == "" {
+		method = "schulze"
+	}
+	days, err := t.h.Range(from, to)
+	if err != nil {
+		return ranking{}, err
+	}
+	placed, err := aggregate.RankDays(method, days)
+	if err != nil {
+		return ranking{}, err
+	}
+	return ranking{from: from, to: to, method: method, days: len(days), placed: placed}, nil
+}
+
+// window parses an optional from and to, defaulting to to the most recent recorded day
+// and from to defaultWindow days ending on to.
+func (t tools) window(fromV, toV string) (from, to time.Time, err error) {
+	if from, to, err = parseRange(fromV, toV); err != nil {
+		return from, to, err
+	}
+	if to.IsZero() {
+		if to, err = t.h.Latest(); err != nil {
+			return from, to, err
+		}
+	}
+	if from.IsZero() {
+		from = to.AddDate(0, 0, -(defaultWindow - 1))
+	}
+	if from.After(to) {
+		return from, to, fmt.Errorf("to %s is before from %s", to.Format(time.DateOnly), from.Format(time.DateOnly))
+	}
+	return from, to, nil
+}
+
+// parseRange parses an optional from and to; a missing one is zero.
+func parseRange(fromV, toV string) (from, to time.Time, err error) {
+	if fromV != "" {
+		if from, err = parseDate("from", fromV); err != nil {
+			return from, to, err
+		}
+	}
+	if toV != "" {
+		if to, err = parseDate("to", toV); err != nil {
+			return from, to, err
+		}
+	}
+	if !from.IsZero() && !to.IsZero() && to.Before(from) {
+		return from, to, fmt.Errorf("to %s is before from %s", toV, fromV)
+	}
+	return from, to, nil
+}
+
+func parseDate(name, v string) (time.Time, error) {
+	d, err := time.Parse(time.DateOnly, v)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%s %q is not a YYYY-MM-DD date", name, v)
+	}
+	return d, nil
+}
+
+func countOr(n int) int {
+	if n <= 0 {
+		return 20
+	}
+	return n
+}
@@ -0,0 +1,151 @@
+package mcpserver
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/fzerorubigd/bgg-hotness/internal/history"
+	"github.com/fzerorubigd/bgg-hotness/internal/snapshot"
+)
+
+// connect runs the server over a pair of pipes, the same newline-delimited JSON-RPC
+// it speaks on stdin and stdout, and returns a client session talking to it.
+func connect(t *testing.T) *mcp.ClientSession {
+	t.Helper()
+	s := snapshot.Store{Dir: t.TempDir()}
+	header := []string{"Rank", "BGGID", "Change", "Link", "Name"}
+	days := map[string][][]string{
+		// The previous week: 1 leads, 4 is on the list.
+		"2026-08-01": {header, {"1", "1", "", "", "Alpha"}, {"2", "4", "", "", "Delta"}, {"3", "2", "", "", "Beta"}},
+		"2026-08-02": {header, {"1", "1", "", "", "Alpha"}, {"2", "4", "", "", "Delta"}, {"3", "2", "", "", "Beta"}},
+		// This week: 3 arrives on top, 4 is gone.
+		"2026-08-03": {header, {"1", "3", "", "", "Gamma"}, {"2", "2", "1", "", "Beta"}, {"3", "1", "-2", "", "Alpha"}},
+		"2026-08-04": {header, {"1", "3", "", "", "Gamma"}, {"2", "2", "", "", "Beta"}, {"3", "1", "", "", "Alpha"}},
+	}
+	for title, rows := range days {
+		if err := s.Write(title, rows); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	serverIn, clientOut := io.Pipe()
+	clientIn, serverOut := io.Pipe()
+	done := make(chan error, 1)
+	go func() { done <- Serve(ctx, history.History{Store: s}, serverIn, serverOut) }()
+
+	client := mcp.NewClient(&mcp.Implementation{Name: "test", Version: "0"}, nil)
+	cs, err := client.Connect(ctx, &mcp.IOTransport{Reader: clientIn, Writer: clientOut}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		cs.Close()
+		cancel()
+		<-done
+	})
+	return cs
+}
+
+// call calls a tool and decodes its structured result into out.
+func call(t *testing.T, cs *mcp.ClientSession, name string, args map[string]any, out any) *mcp.CallToolResult {
+	t.Helper()
+	res, err := cs.CallTool(context.Background(), &mcp.CallToolParams{Name: name, Arguments: args})
+	if err != nil {
+		t.Fatalf("%s: %v", name, err)
+	}
+	if out != nil && !res.IsError {
+		b, err := json.Marshal(res.StructuredContent)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := json.Unmarshal(b, out); err != nil {
+			t.Fatalf("%s: decode: %v", name, err)
+		}
+	}
+	return res
+}
+
+func TestListTools(t *testing.T) {
+	cs := connect(t)
+	res, err := cs.ListTools(context.Background(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, tool := range res.Tools {
+		names = append(names, tool.Name)
+	}
+	sort.Strings(names)
+	want := []string{"aggregate_range", "compare_periods", "game_history", "hotness_on_date"}
+	if !reflect.DeepEqual(names, want) {
+		t.Errorf("tools = %v, want %v", names, want)
+	}
+}
+
+func TestHotnessOnDate(t *testing.T) {
+	cs := connect(t)
+	var out dayOutput
+	call(t, cs, "hotness_on_date", map[string]any{"date": "latest"}, &out)
+	if out.Date != "2026-08-04" || len(out.Games) != 3 || out.Games[0].Name != "Gamma" {
+		t.Errorf("latest = %+v", out)
+	}
+	if res := call(t, cs, "hotness_on_date", map[string]any{"date": "2026-09-01"}, nil); !res.IsError {
+		t.Error("a day not in the store is not a tool error")
+	}
+}
+
+func TestAggregateRange(t *testing.T) {
+	cs := connect(t)
+	var out rangeOutput
+	call(t, cs, "aggregate_range", map[string]any{"from": "2026-08-03", "method": "borda", "count": 2}, &out)
+	if out.From != "2026-08-03" || out.To != "2026-08-04" || out.Days != 2 || out.Method != "borda" {
+		t.Errorf("range = %+v", out)
+	}
+	want := []game{{Rank: 1, ID: 3, Name: "Gamma", Score: 6}, {Rank: 2, ID: 2, Name: "Beta", Score: 4}}
+	if !reflect.DeepEqual(out.Games, want) {
+		t.Errorf("games = %+v, want %+v", out.Games, want)
+	}
+}
+
+func TestGameHistory(t *testing.T) {
+	cs := connect(t)
+	var out gameOutput
+	call(t, cs, "game_history", map[string]any{"id": 4}, &out)
+	if out.Name != "Delta" || out.Days != 2 || out.Total != 4 || out.Latest != 0 || out.CurrentStreak != 0 || out.LongestStreak != 2 || out.Last != "2026-08-02" {
+		t.Errorf("game 4 = %+v", out)
+	}
+	if len(out.History) != 4 || out.History[3] != (point{"2026-08-04", 0}) {
+		t.Errorf("history = %+v", out.History)
+	}
+	if res := call(t, cs, "game_history", map[string]any{"id": 99}, nil); !res.IsError {
+		t.Error("a game never on the list is not a tool error")
+	}
+}
+
+func TestComparePeriods(t *testing.T) {
+	cs := connect(t)
+	var out compareOutput
+	// The two days from the 3rd against the two days before them by default.
+	call(t, cs, "compare_periods", map[string]any{"from": "2026-08-03", "to": "2026-08-04"}, &out)
+	if out.Previous.From != "2026-08-01" || out.Previous.To != "2026-08-02" {
+		t.Errorf("previous period = %s..%s", out.Previous.From, out.Previous.To)
+	}
+	want := []move{
+		{Rank: 1, PreviousRank: 0, Change: 0, New: true, ID: 3, Name: "Gamma"},
+		{Rank: 2, PreviousRank: 3, Change: 1, ID: 2, Name: "Beta"},
+		{Rank: 3, PreviousRank: 1, Change: -2, ID: 1, Name: "Alpha"},
+	}
+	if !reflect.DeepEqual(out.Games, want) {
+		t.Errorf("games = %+v, want %+v", out.Games, want)
+	}
+	if len(out.Dropped) != 1 || out.Dropped[0].ID != 4 || out.Dropped[0].Rank != 2 {
+		t.Errorf("dropped = %+v", out.Dropped)
+	}
+}
@@ -0,0 +1,166 @@
+// Package output is where a command's result goes. Under GitHub Actions that is the
+// "data_array" heredoc gsheet.action reads from $GITHUB_OUTPUT; anywhere else it is
+// more useful as a table to read, or a JSON, CSV or Markdown file to keep. A command
+// builds one Result and hands it to the Sink the -output flag names.
+package output
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/fzerorubigd/bgg-hotness/internal/gsheet"
+)
+
+// Result is what a command produced: a titled table, header row first, and the
+// commands that record it on the spreadsheet.
+type Result struct {
+	Title    string
+	Rows     [][]string
+	Commands gsheet.List
+}
+
+// Sink writes a Result somewhere.
+type Sink interface {
+	Write(r Result) error
+}
+
+// Kinds lists the sink kinds New accepts, for flag help.
+const Kinds = "actions, json, csv, markdown, table"
+
+// Default is the sink a command uses without -output: the Actions heredoc when it runs
+// as a GitHub Actions step (GITHUB_OUTPUT is set), the terminal table otherwise.
+func Default() string {
+	if os.Getenv("GITHUB_OUTPUT") != "" {
+		return "actions"
+	}
+	return "table"
+}
+
+// New returns the sink spec names. spec is KIND or KIND:PATH; without a path the sink
+// writes to stdout, with one it replaces the file at PATH.
+func New(spec string, stdout io.Writer) (Sink, error) {
+	kind, path, _ := strings.Cut(spec, ":")
+	var enc func(io.Writer, Result) error
+	switch kind {
+	case "actions":
+		enc = writeActions
+	case "json":
+		enc = writeJSON
+	case "csv":
+		enc = writeCSV
+	case "markdown", "md":
+		enc = writeMarkdown
+	case "table":
+		enc = writeTable
+	default:
+		return nil, fmt.Errorf("unknown output %q, want one of %s", kind, Kinds)
+	}
+	if path == "" {
+		return writerSink{w: stdout, enc: enc}, nil
+	}
+	return fileSink{path: path, enc: enc}, nil
+}
+
+type writerSink struct {
+	w   io.Writer
+	enc func(io.Writer, Result) error
+}
+
+func (s writerSink) Write(r Result) error { return s.enc(s.w, r) }
+
+// fileSink writes the whole result to a temp file and renames it over path, so a
+// reader never sees a half-written file.
+type fileSink struct {
+	path string
+	enc  func(io.Writer, Result) error
+}
+
+func (s fileSink) Write(r Result) error {
+	var b bytes.Buffer
+	if err := s.enc(&b, r); err != nil {
+		return err
+	}
+	if dir := filepath.Dir(s.path); dir != "." {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return err
+		}
+	}
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, b.Bytes(), 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, s.path)
+}
+
+func writeActions(w io.Writer, r Result) error {
+	return gsheet.Write(w, r.Commands)
+}
+
+// writeJSON writes the table, not the commands: the commands are an instruction to
+// the action, while a JSON file is for whoever reads the result.
+func writeJSON(w io.Writer, r Result) error {
+	doc := struct {
+		Title   string     `json:"title"`
+		Columns []string   `json:"columns"`
+		Rows    [][]string `json:"rows"`
+	}{Title: r.Title, Columns: []string{}, Rows: [][]string{}}
+	if len(r.Rows) > 0 {
+		doc.Columns, doc.Rows = r.Rows[0], r.Rows[1:]
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(doc)
+}
+
+func writeCSV(w io.Writer, r Result) error {
+	cw := csv.NewWriter(w)
+	return cw.WriteAll(r.Rows)
+}
+
+func writeMarkdown(w io.Writer, r Result) error {
+	var b strings.Builder
+	if r.Title != "" {
+		fmt.Fprintf(&b, "### %s\n\n", r.Title)
+	}
+	if len(r.Rows) > 0 {
+		markdownRow(&b, r.Rows[0])
+		b.WriteString("|")
+		for range r.Rows[0] {
+			b.WriteString("---|")
+		}
+		b.WriteString("\n")
+		for _, row := range r.Rows[1:] {
+			markdownRow(&b, row)
+		}
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+var markdownEscaper = strings.NewReplacer("|", `\|`, "\n", " ")
+
+func markdownRow(b *strings.Builder, row []string) {
+	b.WriteString("|")
+	for _, cell := range row {
+		b.WriteString(" " + markdownEscaper.Replace(cell) + " |")
+	}
+	b.WriteString("\n")
+}
+
+func writeTable(w io.Writer, r Result) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	if r.Title != "" {
+		fmt.Fprintln(tw, r.Title)
+	}
+	for _, row := range r.Rows {
+		fmt.Fprintln(tw, strings.Join(row, "\t"))
+	}
+	return tw.Flush()
+}
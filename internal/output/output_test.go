@@ -0,0 +1,124 @@
+package output
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/fzerorubigd/bgg-hotness/internal/gsheet"
+)
+
+var result = Result{
+	Title: "2026-08-01_14-days",
+	Rows: [][]string{
+		{"Rank", "BGGID", "Wins", "Link", "Name"},
+		{"1", "174430", "49", "https://boardgamegeek.com/boardgame/174430/", "Gloomhaven"},
+		{"2", "224517", "48", "https://boardgamegeek.com/boardgame/224517/", "Brass: Birmingham | Lancashire"},
+	},
+	Commands: gsheet.List{gsheet.AddWorksheet{WorksheetTitle: "2026-08-01_14-days"}},
+}
+
+func render(t *testing.T, spec string) string {
+	t.Helper()
+	var b strings.Builder
+	s, err := New(spec, &b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Write(result); err != nil {
+		t.Fatal(err)
+	}
+	return b.String()
+}
+
+// The actions output is the heredoc gsheet.action consumes; it must carry the commands
+// and nothing else.
+func TestActions(t *testing.T) {
+	l, err := gsheet.Read(strings.NewReader(render(t, "actions")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(l) != 1 || l[0] != result.Commands[0] {
+		t.Errorf("commands = %+v", l)
+	}
+}
+
+func TestJSON(t *testing.T) {
+	var doc struct {
+		Title   string
+		Columns []string
+		Rows    [][]string
+	}
+	if err := json.Unmarshal([]byte(render(t, "json")), &doc); err != nil {
+		t.Fatal(err)
+	}
+	if doc.Title != result.Title || len(doc.Columns) != 5 || len(doc.Rows) != 2 || doc.Rows[1][4] != "Brass: Birmingham | Lancashire" {
+		t.Errorf("json = %+v", doc)
+	}
+}
+
+func TestCSV(t *testing.T) {
+	got := render(t, "csv")
+	want := "Rank,BGGID,Wins,Link,Name\n"
+	if !strings.HasPrefix(got, want) || strings.Count(got, "\n") != 3 {
+		t.Errorf("csv =\n%s", got)
+	}
+}
+
+func TestMarkdown(t *testing.T) {
+	got := render(t, "markdown")
+	for _, want := range []string{
+		"### 2026-08-01_14-days\n\n",
+		"| Rank | BGGID | Wins | Link | Name |\n|---|---|---|---|---|\n",
+		`| Brass: Birmingham \| Lancashire |`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("markdown missing %q:\n%s", want, got)
+		}
+	}
+}
+
+func TestTable(t *testing.T) {
+	got := render(t, "table")
+	lines := strings.Split(strings.TrimSpace(got), "\n")
+	if len(lines) != 4 || lines[0] != result.Title {
+		t.Fatalf("table =\n%s", got)
+	}
+	// Columns line up: "Name" starts where the names do.
+	if strings.Index(lines[1], "Name") != strings.Index(lines[2], "Gloomhaven") {
+		t.Errorf("columns are not aligned:\n%s", got)
+	}
+}
+
+func TestFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "out", "ranking.md")
+	if out := render(t, "markdown:"+path); out != "" {
+		t.Errorf("a file sink wrote to stdout: %q", out)
+	}
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(string(b), "### 2026-08-01_14-days") {
+		t.Errorf("file = %s", b)
+	}
+}
+
+func TestUnknownKind(t *testing.T) {
+	if _, err := New("yaml", os.Stdout); err == nil {
+		t.Error("an unknown kind should fail")
+	}
+}
+
+func TestDefault(t *testing.T) {
+	t.Setenv("GITHUB_OUTPUT", "/tmp/out")
+	if Default() != "actions" {
+		t.Error("under Actions the default should be the heredoc")
+	}
+	t.Setenv("GITHUB_OUTPUT", "")
+	if Default() != "table" {
+		t.Error("outside Actions the default should be the table")
+	}
+}
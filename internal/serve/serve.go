@@ -0,0 +1,335 @@
+// Package serve is the serve subcommand: a read-only HTTP/JSON API over the daily
+// lists in the snapshot store, for the podcast website and the Discord bot, which
+// should not need access to the spreadsheet to ask what was hot.
+//
+//	GET /hotness/{date}                  the list on a day, YYYY-MM-DD or "latest"
+//	GET /aggregate?from=&to=&method=     the days in [from, to] ranked into one list
+//	GET /games/{id}/history?from=&to=    a game's rank on each day, 0 when off the list
+//	GET /games/{id}/stats?from=&to=      days on the list, best and mean rank, streaks
+//
+// from and to are dates and both optional: /aggregate defaults to the last 14 stored
+// days, the game endpoints to everything. The ranking is aggregate.Rank, so method is
+// one of aggregate.Methods and schulze, what the aggregate command publishes, without
+// it. Errors are {"error": "..."} with a 400 for a bad request and a 404 for a date or
+// game the store does not have.
+package serve
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/fzerorubigd/bgg-hotness/internal/aggregate"
+	"github.com/fzerorubigd/bgg-hotness/internal/config"
+	"github.com/fzerorubigd/bgg-hotness/internal/history"
+	"github.com/fzerorubigd/bgg-hotness/internal/snapshot"
+)
+
+// defaultWindow is how many days /aggregate ranks without a from.
+const defaultWindow = 14
+
+// Run serves the API until ctx is cancelled.
+func Run(ctx context.Context, cfg config.Config, args []string) error {
+	fs := flag.NewFlagSet("serve", flag.ContinueOnError)
+	var addr, archiveDir string
+	fs.StringVar(&addr, "addr", "localhost:8080", "Address to listen on")
+	fs.StringVar(&archiveDir, "archive-dir", cfg.ArchiveDir, "Directory of daily lists to serve, as fetch -archive-dir and cleanup write it")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if archiveDir == "" {
+		return errors.New("-archive-dir (or archive_dir in the config) is required")
+	}
+
+	srv := &http.Server{
+		Handler:           Handler(history.History{Store: snapshot.Store{Dir: archiveDir}}),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	l, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	log.Printf("serving %s on http://%s", archiveDir, l.Addr())
+
+	errc := make(chan error, 1)
+	go func() { errc <- srv.Serve(l) }()
+	select {
+	case err := <-errc:
+		return err
+	case <-ctx.Done():
+		shutdown, cnl := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cnl()
+		return srv.Shutdown(shutdown)
+	}
+}
+
+// Handler returns the API over h.
+func Handler(h history.History) http.Handler {
+	s := server{h: h}
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /hotness/{date}", s.hotness)
+	mux.HandleFunc("GET /aggregate", s.aggregate)
+	mux.HandleFunc("GET /games/{id}/history", s.gameHistory)
+	mux.HandleFunc("GET /games/{id}/stats", s.gameStats)
+	return mux
+}
+
+type server struct {
+	h history.History
+}
+
+// httpError is an error with the status it should be served with.
+type httpError struct {
+	status int
+	err    error
+}
+
+func (e httpError) Error() string { return e.err.Error() }
+
+func badRequest(format string, a ...any) error {
+	return httpError{http.StatusBadRequest, fmt.Errorf(format, a...)}
+}
+
+func notFound(format string, a ...any) error {
+	return httpError{http.StatusNotFound, fmt.Errorf(format, a...)}
+}
+
+type entry struct {
+	Rank   int    `json:"rank"`
+	ID     int64  `json:"id"`
+	Change string `json:"change,omitempty"`
+	Score  int    `json:"score,omitempty"`
+	Name   string `json:"name"`
+}
+
+type dayResponse struct {
+	Date    string  `json:"date"`
+	Entries []entry `json:"entries"`
+}
+
+func (s server) hotness(w http.ResponseWriter, r *http.Request) {
+	date, err := s.date(r.PathValue("date"))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	d, err := s.h.Day(date)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	resp := dayResponse{Date: d.Date.Format(time.DateOnly), Entries: []entry{}}
+	for _, e := range d.Entries {
+		resp.Entries = append(resp.Entries, entry{Rank: e.Rank, ID: e.ID, Change: e.Change, Name: e.Name})
+	}
+	writeJSON(w, resp)
+}
+
+// date parses a {date} path value, where "latest" is the most recent stored day.
+func (s server) date(v string) (time.Time, error) {
+	if v == "latest" {
+		return s.h.Latest()
+	}
+	d, err := time.Parse(time.DateOnly, v)
+	if err != nil {
+		return time.Time{}, badRequest("date %q is not YYYY-MM-DD or latest", v)
+	}
+	return d, nil
+}
+
+type aggregateResponse struct {
+	From    string  `json:"from"`
+	To      string  `json:"to"`
+	Method  string  `json:"method"`
+	Days    int     `json:"days"`
+	Entries []entry `json:"entries"`
+}
+
+func (s server) aggregate(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	from, to, err := dateRange(q.Get("from"), q.Get("to"))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	method := q.Get("method")
+	if method == "" {
+		method = "schulze"
+	}
+	count := 50
+	if v := q.Get("count"); v != "" {
+		if count, err = strconv.Atoi(v); err != nil || count < 0 {
+			writeError(w, badRequest("count %q is not a number of games", v))
+			return
+		}
+	}
+	if to.IsZero() {
+		if to, err = s.h.Latest(); err != nil {
+			writeError(w, err)
+			return
+		}
+	}
+	if from.IsZero() {
+		from = to.AddDate(0, 0, -(defaultWindow - 1))
+	}
+
+	days, err := s.h.Range(from, to)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	placed, err := aggregate.RankDays(method, days)
+	if err != nil {
+		writeError(w, badRequest("%v", err))
+		return
+	}
+	if count > 0 && len(placed) > count {
+		placed = placed[:count]
+	}
+	resp := aggregateResponse{
+		From:    from.Format(time.DateOnly),
+		To:      to.Format(time.DateOnly),
+		Method:  method,
+		Days:    len(days),
+		Entries: []entry{},
+	}
+	for _, p := range placed {
+		resp.Entries = append(resp.Entries, entry{Rank: p.Rank, ID: p.ID, Score: p.Score, Name: p.Name})
+	}
+	writeJSON(w, resp)
+}
+
+type point struct {
+	Date string `json:"date"`
+	Rank int    `json:"rank"`
+}
+
+type historyResponse struct {
+	ID     int64   `json:"id"`
+	Name   string  `json:"name"`
+	Points []point `json:"points"`
+}
+
+func (s server) gameHistory(w http.ResponseWriter, r *http.Request) {
+	id, days, err := s.game(r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	resp := historyResponse{ID: id, Name: history.Names(days)[id], Points: []point{}}
+	for _, p := range history.Game(days, id) {
+		resp.Points = append(resp.Points, point{Date: p.Date.Format(time.DateOnly), Rank: p.Rank})
+	}
+	writeJSON(w, resp)
+}
+
+type statsResponse struct {
+	ID            int64   `json:"id"`
+	Name          string  `json:"name"`
+	Days          int     `json:"days"`
+	Total         int     `json:"total"`
+	Best          int     `json:"best"`
+	Mean          float64 `json:"mean"`
+	Latest        int     `json:"latest"`
+	CurrentStreak int     `json:"current_streak"`
+	LongestStreak int     `json:"longest_streak"`
+	First         string  `json:"first"`
+	Last          string  `json:"last"`
+}
+
+func (s server) gameStats(w http.ResponseWriter, r *http.Request) {
+	id, days, err := s.game(r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	st := history.Summarize(history.Game(days, id))
+	writeJSON(w, statsResponse{
+		ID:            id,
+		Name:          history.Names(days)[id],
+		Days:          st.Days,
+		Total:         st.Total,
+		Best:          st.Best,
+		Mean:          st.Mean,
+		Latest:        st.Latest,
+		CurrentStreak: st.CurrentStreak,
+		LongestStreak: st.LongestStreak,
+		First:         st.First.Format(time.DateOnly),
+		Last:          st.Last.Format(time.DateOnly),
+	})
+}
+
+// game reads the {id} and the from/to of a game request and returns the days in that
+// range. A game that is on none of them is a 404, not an all-zero history.
+func (s server) game(r *http.Request) (int64, []history.Day, error) {
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		return 0, nil, badRequest("game id %q is not a number", r.PathValue("id"))
+	}
+	q := r.URL.Query()
+	from, to, err := dateRange(q.Get("from"), q.Get("to"))
+	if err != nil {
+		return 0, nil, err
+	}
+	days, err := s.h.Range(from, to)
+	if err != nil {
+		return 0, nil, err
+	}
+	for _, d := range days {
+		for _, e := range d.Entries {
+			if e.ID == id {
+				return id, days, nil
+			}
+		}
+	}
+	return 0, nil, notFound("game %d was not on the hot list in that range", id)
+}
+
+// dateRange parses the optional from and to query values; a missing one is zero.
+func dateRange(fromV, toV string) (from, to time.Time, err error) {
+	if fromV != "" {
+		if from, err = time.Parse(time.DateOnly, fromV); err != nil {
+			return from, to, badRequest("from %q is not YYYY-MM-DD", fromV)
+		}
+	}
+	if toV != "" {
+		if to, err = time.Parse(time.DateOnly, toV); err != nil {
+			return from, to, badRequest("to %q is not YYYY-MM-DD", toV)
+		}
+	}
+	if !from.IsZero() && !to.IsZero() && to.Before(from) {
+		return from, to, badRequest("to %s is before from %s", toV, fromV)
+	}
+	return from, to, nil
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log.Printf("write response: %v", err)
+	}
+}
+
+func writeError(w http.ResponseWriter, err error) {
+	status := http.StatusInternalServerError
+	var he httpError
+	switch {
+	case errors.As(err, &he):
+		status = he.status
+	case errors.Is(err, history.ErrNoDay):
+		status = http.StatusNotFound
+	}
+	if status == http.StatusInternalServerError {
+		log.Printf("serve: %v", err)
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+}
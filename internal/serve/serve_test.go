@@ -0,0 +1,133 @@
+package serve
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/fzerorubigd/bgg-hotness/internal/history"
+	"github.com/fzerorubigd/bgg-hotness/internal/snapshot"
+)
+
+func testServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	s := snapshot.Store{Dir: t.TempDir()}
+	header := []string{"Rank", "BGGID", "Change", "Link", "Name"}
+	days := map[string][][]string{
+		"2026-08-01": {header, {"1", "1", "0", "", "Alpha"}, {"2", "2", "0", "", "Beta"}, {"3", "3", "0", "", "Gamma"}},
+		"2026-08-02": {header, {"1", "1", "0", "", "Alpha"}, {"2", "2", "0", "", "Beta"}, {"3", "3", "0", "", "Gamma"}},
+		"2026-08-03": {header, {"1", "3", "2", "", "Gamma"}, {"2", "2", "0", "", "Beta"}, {"3", "1", "-2", "", "Alpha"}},
+	}
+	for title, rows := range days {
+		if err := s.Write(title, rows); err != nil {
+			t.Fatal(err)
+		}
+	}
+	srv := httptest.NewServer(Handler(history.History{Store: s}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+// get fetches path and decodes the JSON body into v, returning the status.
+func get(t *testing.T, srv *httptest.Server, path string, v any) int {
+	t.Helper()
+	resp, err := http.Get(srv.URL + path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if ct := resp.Header.Get("Content-Type"); ct != "application/json" {
+		t.Errorf("GET %s Content-Type = %q", path, ct)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		t.Fatalf("GET %s: decode: %v", path, err)
+	}
+	return resp.StatusCode
+}
+
+func TestHotness(t *testing.T) {
+	srv := testServer(t)
+	var latest dayResponse
+	if status := get(t, srv, "/hotness/latest", &latest); status != http.StatusOK {
+		t.Fatalf("status %d", status)
+	}
+	if latest.Date != "2026-08-03" || len(latest.Entries) != 3 {
+		t.Fatalf("latest = %+v", latest)
+	}
+	if want := (entry{Rank: 1, ID: 3, Change: "2", Name: "Gamma"}); latest.Entries[0] != want {
+		t.Errorf("latest first entry = %+v, want %+v", latest.Entries[0], want)
+	}
+
+	var d dayResponse
+	get(t, srv, "/hotness/2026-08-01", &d)
+	if d.Date != "2026-08-01" || d.Entries[0].Name != "Alpha" {
+		t.Errorf("2026-08-01 = %+v", d)
+	}
+}
+
+func TestAggregate(t *testing.T) {
+	srv := testServer(t)
+	ids := func(r aggregateResponse) []int64 {
+		out := make([]int64, len(r.Entries))
+		for i, e := range r.Entries {
+			out[i] = e.ID
+		}
+		return out
+	}
+
+	var def aggregateResponse
+	if status := get(t, srv, "/aggregate", &def); status != http.StatusOK {
+		t.Fatalf("status %d", status)
+	}
+	if def.Method != "schulze" || def.Days != 3 || def.To != "2026-08-03" || def.From != "2026-07-21" {
+		t.Errorf("default window = %+v", def)
+	}
+	if got, want := ids(def), []int64{1, 2, 3}; !reflect.DeepEqual(got, want) {
+		t.Errorf("schulze = %v, want %v", got, want)
+	}
+	if def.Entries[0].Name != "Alpha" {
+		t.Errorf("names not filled in: %+v", def.Entries[0])
+	}
+
+	var one aggregateResponse
+	get(t, srv, "/aggregate?from=2026-08-03&to=2026-08-03&method=borda&count=2", &one)
+	if got, want := ids(one), []int64{3, 2}; !reflect.DeepEqual(got, want) || one.Days != 1 {
+		t.Errorf("borda on the 3rd = %v over %d day(s), want %v over 1", got, one.Days, want)
+	}
+}
+
+func TestGame(t *testing.T) {
+	srv := testServer(t)
+	var h historyResponse
+	get(t, srv, "/games/3/history?from=2026-08-02", &h)
+	want := historyResponse{ID: 3, Name: "Gamma", Points: []point{{"2026-08-02", 3}, {"2026-08-03", 1}}}
+	if !reflect.DeepEqual(h, want) {
+		t.Errorf("history = %+v, want %+v", h, want)
+	}
+
+	var st statsResponse
+	get(t, srv, "/games/1/stats", &st)
+	if st.Days != 3 || st.Best != 1 || st.Latest != 3 || st.CurrentStreak != 3 || st.First != "2026-08-01" || st.Name != "Alpha" {
+		t.Errorf("stats = %+v", st)
+	}
+}
+
+func TestErrors(t *testing.T) {
+	srv := testServer(t)
+	for path, want := range map[string]int{
+		"/hotness/2026-09-01":                      http.StatusNotFound,
+		"/hotness/yesterday":                       http.StatusBadRequest,
+		"/aggregate?method=approval":               http.StatusBadRequest,
+		"/aggregate?from=2026-08-03&to=2026-08-01": http.StatusBadRequest,
+		"/aggregate?count=-1":                      http.StatusBadRequest,
+		"/games/99/stats":                          http.StatusNotFound,
+		"/games/x/history":                         http.StatusBadRequest,
+	} {
+		var body map[string]string
+		if status := get(t, srv, path, &body); status != want || body["error"] == "" {
+			t.Errorf("GET %s = %d %v, want %d with an error", path, status, body, want)
+		}
+	}
+}
@@ -0,0 +1,81 @@
+// Package sheetexec is the exec subcommand: it runs a command list, as hotness,
+// aggregate or cleanup print it, without gsheet.action:
+//
+//	bgg-hotness fetch -output=actions | bgg-hotness exec -document-id=...
+//	bgg-hotness fetch -output=actions | bgg-hotness exec -local-dir=./sheets
+//
+// With -local-dir the spreadsheet is a directory of CSV files, one per worksheet.
+package sheetexec
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/fzerorubigd/bgg-hotness/internal/config"
+	"github.com/fzerorubigd/bgg-hotness/internal/gsheet"
+	"github.com/fzerorubigd/bgg-hotness/internal/sheetsclient"
+	"github.com/fzerorubigd/bgg-hotness/internal/snapshot"
+)
+
+// Run reads a command list from stdin and runs it, printing the results as JSON.
+func Run(ctx context.Context, cfg config.Config, args []string) error {
+	fs := flag.NewFlagSet("exec", flag.ContinueOnError)
+	var (
+		spreadsheetId string
+		localDir      string
+		check         bool
+		sheetsConfig  = sheetsclient.ConfigFromEnv()
+	)
+	sheetsConfig.Endpoint = cfg.SheetsEndpoint
+	fs.StringVar(&spreadsheetId, "document-id", cfg.DocumentID, "The document id to run the commands against")
+	fs.StringVar(&localDir, "local-dir", os.Getenv("SHEETS_LOCAL_DIR"), "Directory of CSV worksheets to run the commands against instead of a spreadsheet")
+	fs.StringVar(&sheetsConfig.Endpoint, "sheets-endpoint", sheetsConfig.Endpoint, "Sheets API base URL, e.g. a local stand-in (default the Google API)")
+	fs.BoolVar(&check, "check", false, "Only validate the commands against the current worksheets, without running them")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	commands, err := gsheet.Read(os.Stdin)
+	if err != nil {
+		return err
+	}
+
+	var backend gsheet.Backend
+	if localDir != "" {
+		backend = gsheet.LocalBackend{Store: snapshot.Store{Dir: localDir}}
+	} else {
+		if spreadsheetId == "" {
+			return errors.New("one of -document-id or -local-dir is required")
+		}
+		srv, err := sheetsclient.New(ctx, sheetsConfig)
+		if err != nil {
+			return fmt.Errorf("unable to retrieve Sheets client: %w", err)
+		}
+		backend = gsheet.SheetsBackend{Service: srv, SpreadsheetID: spreadsheetId}
+	}
+
+	if check {
+		titles, err := backend.Worksheets(ctx)
+		if err != nil {
+			return err
+		}
+		if err := gsheet.Validate(commands, titles); err != nil {
+			return err
+		}
+		fmt.Fprintf(os.Stderr, "%d command(s) valid\n", len(commands))
+		return nil
+	}
+
+	results, err := gsheet.Execute(ctx, backend, commands)
+	// Print what did run even on failure: those commands have changed the sheet.
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	if encErr := enc.Encode(results); encErr != nil {
+		return encErr
+	}
+	return err
+}
@@ -0,0 +1,108 @@
+// Package sheetsclient builds the Google Sheets client the commands share. It decides
+// how to authenticate from a Config, usually read from the environment, so the same
+// binary runs under GitHub Actions (a service account in two secrets), on a laptop
+// (a JSON key file or Application Default Credentials) and against a local Sheets
+// stand-in (an endpoint override and no credentials at all).
+package sheetsclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"golang.org/x/oauth2/google"
+	"golang.org/x/oauth2/jwt"
+	"google.golang.org/api/option"
+	"google.golang.org/api/sheets/v4"
+)
+
+// Config selects the credentials and endpoint of the Sheets client. The first
+// credential source that is set wins, in field order: CredentialsFile, then the inline
+// ClientEmail/PrivateKey pair, then Application Default Credentials.
+type Config struct {
+	// CredentialsFile is a service-account JSON key file.
+	CredentialsFile string
+	// ClientEmail and PrivateKey are a service account given inline, the way the
+	// workflows pass them as secrets. PrivateKey may carry literal `\n` sequences
+	// instead of newlines, as it does when pasted into a secret.
+	ClientEmail string
+	PrivateKey  string
+	// TokenURL overrides the OAuth2 token endpoint for the service-account sources.
+	// Empty means the one the key file names, or Google's for an inline key.
+	TokenURL string
+	// Endpoint overrides the Sheets API base URL, e.g. a local stand-in. With an
+	// endpoint set and no credential configured the client sends no credentials at
+	// all rather than falling back to ADC, since a stand-in has nothing to check them
+	// against and a developer machine may have ADC for a real project.
+	Endpoint string
+}
+
+// ConfigFromEnv reads a Config from the GSHEET_* variables the workflows already set,
+// plus GSHEET_CREDENTIALS_FILE, GSHEET_TOKEN_URL and SHEETS_ENDPOINT.
+func ConfigFromEnv() Config {
+	return Config{
+		CredentialsFile: os.Getenv("GSHEET_CREDENTIALS_FILE"),
+		ClientEmail:     os.Getenv("GSHEET_CLIENT_EMAIL"),
+		PrivateKey:      os.Getenv("GSHEET_PRIVATE_KEY"),
+		TokenURL:        os.Getenv("GSHEET_TOKEN_URL"),
+		Endpoint:        os.Getenv("SHEETS_ENDPOINT"),
+	}
+}
+
+// ClientOptions returns the options that configure a Google API client for c.
+func (c Config) ClientOptions(ctx context.Context) ([]option.ClientOption, error) {
+	var opts []option.ClientOption
+	if c.Endpoint != "" {
+		opts = append(opts, option.WithEndpoint(c.Endpoint))
+	}
+
+	switch {
+	case c.CredentialsFile != "":
+		b, err := os.ReadFile(c.CredentialsFile)
+		if err != nil {
+			return nil, fmt.Errorf("read credentials file: %w", err)
+		}
+		conf, err := google.JWTConfigFromJSON(b, sheets.SpreadsheetsScope)
+		if err != nil {
+			return nil, fmt.Errorf("parse credentials file %q: %w", c.CredentialsFile, err)
+		}
+		if c.TokenURL != "" {
+			conf.TokenURL = c.TokenURL
+		}
+		opts = append(opts, option.WithHTTPClient(conf.Client(ctx)))
+	case c.ClientEmail != "" || c.PrivateKey != "":
+		if c.ClientEmail == "" || c.PrivateKey == "" {
+			return nil, errors.New("GSHEET_CLIENT_EMAIL and GSHEET_PRIVATE_KEY must be set together")
+		}
+		conf := &jwt.Config{
+			Email:      c.ClientEmail,
+			PrivateKey: []byte(strings.ReplaceAll(c.PrivateKey, `\n`, "\n")),
+			TokenURL:   google.JWTTokenURL,
+			Scopes:     []string{sheets.SpreadsheetsScope},
+		}
+		if c.TokenURL != "" {
+			conf.TokenURL = c.TokenURL
+		}
+		opts = append(opts, option.WithHTTPClient(conf.Client(ctx)))
+	case c.Endpoint != "":
+		opts = append(opts, option.WithoutAuthentication())
+	default:
+		creds, err := google.FindDefaultCredentials(ctx, sheets.SpreadsheetsScope)
+		if err != nil {
+			return nil, fmt.Errorf("no Sheets credentials configured and no Application Default Credentials: %w", err)
+		}
+		opts = append(opts, option.WithTokenSource(creds.TokenSource))
+	}
+	return opts, nil
+}
+
+// New returns a Sheets service for c.
+func New(ctx context.Context, c Config) (*sheets.Service, error) {
+	opts, err := c.ClientOptions(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return sheets.NewService(ctx, opts...)
+}
@@ -0,0 +1,498 @@
+// Package sheetstest is a local stand-in for the parts of Google Sheets the commands
+// use: spreadsheets.get, values get/batchGet/update/append, batchUpdate addSheet and
+// deleteSheet, and the CSV export URL aggregate downloads its ballots from. It keeps
+// the spreadsheets in memory behind an httptest server, so a test (or CI) can run the
+// daily -> aggregate -> cleanup cycle with no Google account.
+//
+// It implements only what those calls need, with the Sheets semantics the commands
+// rely on: cell values are strings, reads omit trailing empty cells and rows, and a
+// worksheet title is unique within a spreadsheet. Anything else answers 400 or 404.
+package sheetstest
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/fzerorubigd/bgg-hotness/internal/gsheet"
+)
+
+// Server is a fake Sheets API. Point sheetsclient.Config.Endpoint at URL()+"/" and the
+// CSV export base at URL().
+type Server struct {
+	*httptest.Server
+
+	mu     sync.Mutex
+	docs   map[string]*doc
+	nextID int64
+}
+
+type doc struct {
+	sheets []*sheet
+}
+
+type sheet struct {
+	id    int64
+	title string
+	rows  [][]string
+}
+
+// NewServer starts a Server with no spreadsheets. Close it when done.
+func NewServer() *Server {
+	s := &Server{docs: map[string]*doc{}, nextID: 1}
+	s.Server = httptest.NewServer(s)
+	return s
+}
+
+// AddSheet creates a worksheet titled title in spreadsheet docID (creating the
+// spreadsheet on first use) holding rows, and returns its sheet id, the gid the CSV
+// export addresses it by. It replaces the rows of an existing worksheet of that title.
+func (s *Server) AddSheet(docID, title string, rows [][]string) int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	d := s.doc(docID)
+	if sh := d.find(title); sh != nil {
+		sh.rows = copyRows(rows)
+		return sh.id
+	}
+	return s.addSheet(d, title, copyRows(rows)).id
+}
+
+// Titles returns the worksheet titles of docID in sheet order.
+func (s *Server) Titles(docID string) []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var res []string
+	if d, ok := s.docs[docID]; ok {
+		for _, sh := range d.sheets {
+			res = append(res, sh.title)
+		}
+	}
+	return res
+}
+
+// Rows returns a copy of the rows of worksheet title in docID, or nil if it does not
+// exist.
+func (s *Server) Rows(docID, title string) [][]string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if d, ok := s.docs[docID]; ok {
+		if sh := d.find(title); sh != nil {
+			return copyRows(sh.rows)
+		}
+	}
+	return nil
+}
+
+func (s *Server) doc(id string) *doc {
+	d, ok := s.docs[id]
+	if !ok {
+		d = &doc{}
+		s.docs[id] = d
+	}
+	return d
+}
+
+func (s *Server) addSheet(d *doc, title string, rows [][]string) *sheet {
+	sh := &sheet{id: s.nextID, title: title, rows: rows}
+	s.nextID++
+	d.sheets = append(d.sheets, sh)
+	return sh
+}
+
+func (d *doc) find(title string) *sheet {
+	for _, sh := range d.sheets {
+		if sh.title == title {
+			return sh
+		}
+	}
+	return nil
+}
+
+func (d *doc) byID(id int64) *sheet {
+	for _, sh := range d.sheets {
+		if sh.id == id {
+			return sh
+		}
+	}
+	return nil
+}
+
+func copyRows(rows [][]string) [][]string {
+	res := make([][]string, len(rows))
+	for i := range rows {
+		res[i] = append([]string(nil), rows[i]...)
+	}
+	return res
+}
+
+// ServeHTTP routes a request by hand rather than through a ServeMux: the API's method
+// suffixes (":batchUpdate", ".../values/{range}:append") sit inside a path segment,
+// and a range is percent-encoded, so the path is split on its escaped form.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if r.URL.Path == "/feeds/download/spreadsheets/Export" {
+		s.export(w, r)
+		return
+	}
+	rest, ok := strings.CutPrefix(r.URL.EscapedPath(), "/v4/spreadsheets/")
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	parts := strings.Split(rest, "/")
+	id, method, _ := strings.Cut(parts[0], ":")
+	docID, err := url.PathUnescape(id)
+	if err != nil {
+		httpError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	switch {
+	case len(parts) == 1 && method == "" && r.Method == http.MethodGet:
+		s.get(w, docID)
+	case len(parts) == 1 && method == "batchUpdate" && r.Method == http.MethodPost:
+		s.batchUpdate(w, r, docID)
+	case len(parts) == 2 && parts[1] == "values:batchGet" && r.Method == http.MethodGet:
+		s.batchGet(w, r, docID)
+	case len(parts) == 3 && parts[1] == "values":
+		escaped, suffix, _ := strings.Cut(parts[2], ":")
+		rng, err := url.PathUnescape(escaped)
+		if err != nil {
+			httpError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		switch {
+		case suffix == "" && r.Method == http.MethodGet:
+			s.valuesGet(w, docID, rng)
+		case suffix == "" && r.Method == http.MethodPut:
+			s.valuesUpdate(w, r, docID, rng)
+		case suffix == "append" && r.Method == http.MethodPost:
+			s.valuesAppend(w, r, docID, rng)
+		default:
+			http.NotFound(w, r)
+		}
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func (s *Server) get(w http.ResponseWriter, docID string) {
+	d, ok := s.docs[docID]
+	if !ok {
+		httpError(w, http.StatusNotFound, "Requested entity was not found.")
+		return
+	}
+	type props struct {
+		SheetID        int64          `json:"sheetId"`
+		Title          string         `json:"title"`
+		Index          int            `json:"index"`
+		GridProperties map[string]int `json:"gridProperties"`
+	}
+	type sheetJSON struct {
+		Properties props `json:"properties"`
+	}
+	out := struct {
+		SpreadsheetID string      `json:"spreadsheetId"`
+		Sheets        []sheetJSON `json:"sheets"`
+	}{SpreadsheetID: docID}
+	for i, sh := range d.sheets {
+		out.Sheets = append(out.Sheets, sheetJSON{Properties: props{
+			SheetID: sh.id, Title: sh.title, Index: i,
+			GridProperties: map[string]int{"rowCount": max(len(sh.rows), 1000), "columnCount": 26},
+		}})
+	}
+	writeJSON(w, out)
+}
+
+func (s *Server) batchUpdate(w http.ResponseWriter, r *http.Request, docID string) {
+	var req struct {
+		Requests []struct {
+			AddSheet *struct {
+				Properties struct {
+					Title string `json:"title"`
+				} `json:"properties"`
+			} `json:"addSheet"`
+			DeleteSheet *struct {
+				SheetID int64 `json:"sheetId"`
+			} `json:"deleteSheet"`
+		} `json:"requests"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httpError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	d, ok := s.docs[docID]
+	if !ok {
+		httpError(w, http.StatusNotFound, "Requested entity was not found.")
+		return
+	}
+	// Validate the whole batch before applying any of it: Sheets applies a batch
+	// atomically, and a half-applied batch would make a test pass for the wrong reason.
+	titles := map[string]bool{}
+	for _, sh := range d.sheets {
+		titles[sh.title] = true
+	}
+	for _, q := range req.Requests {
+		switch {
+		case q.AddSheet != nil:
+			t := q.AddSheet.Properties.Title
+			if titles[t] {
+				httpError(w, http.StatusBadRequest, fmt.Sprintf("A sheet with the name %q already exists.", t))
+				return
+			}
+			titles[t] = true
+		case q.DeleteSheet != nil:
+			if d.byID(q.DeleteSheet.SheetID) == nil {
+				httpError(w, http.StatusBadRequest, fmt.Sprintf("No sheet with id: %d", q.DeleteSheet.SheetID))
+				return
+			}
+		default:
+			httpError(w, http.StatusBadRequest, "unsupported request")
+			return
+		}
+	}
+
+	var replies []map[string]interface{}
+	for _, q := range req.Requests {
+		if q.AddSheet != nil {
+			sh := s.addSheet(d, q.AddSheet.Properties.Title, nil)
+			replies = append(replies, map[string]interface{}{
+				"addSheet": map[string]interface{}{"properties": map[string]interface{}{"sheetId": sh.id, "title": sh.title}},
+			})
+			continue
+		}
+		for i, sh := range d.sheets {
+			if sh.id == q.DeleteSheet.SheetID {
+				d.sheets = append(d.sheets[:i], d.sheets[i+1:]...)
+				break
+			}
+		}
+		replies = append(replies, map[string]interface{}{})
+	}
+	writeJSON(w, map[string]interface{}{"spreadsheetId": docID, "replies": replies})
+}
+
+type valueRange struct {
+	Range          string     `json:"range"`
+	MajorDimension string     `json:"majorDimension"`
+	Values         [][]string `json:"values,omitempty"`
+}
+
+func (s *Server) read(docID, rng string) (valueRange, int, string) {
+	a, err := gsheet.ParseRange(rng)
+	if err != nil {
+		return valueRange{}, http.StatusBadRequest, err.Error()
+	}
+	d, ok := s.docs[docID]
+	if !ok {
+		return valueRange{}, http.StatusNotFound, "Requested entity was not found."
+	}
+	sh := d.find(a.Title)
+	if sh == nil {
+		return valueRange{}, http.StatusBadRequest, "Unable to parse range: " + rng
+	}
+	vr := valueRange{Range: rng, MajorDimension: "ROWS"}
+	for i := a.Row0; i < len(sh.rows) && (a.Row1 < 0 || i <= a.Row1); i++ {
+		var row []string
+		for j := a.Col0; j < len(sh.rows[i]) && (a.Col1 < 0 || j <= a.Col1); j++ {
+			row = append(row, sh.rows[i][j])
+		}
+		vr.Values = append(vr.Values, trimRow(row))
+	}
+	for len(vr.Values) > 0 && len(vr.Values[len(vr.Values)-1]) == 0 {
+		vr.Values = vr.Values[:len(vr.Values)-1]
+	}
+	return vr, http.StatusOK, ""
+}
+
+func (s *Server) valuesGet(w http.ResponseWriter, docID, rng string) {
+	vr, code, msg := s.read(docID, rng)
+	if code != http.StatusOK {
+		httpError(w, code, msg)
+		return
+	}
+	writeJSON(w, vr)
+}
+
+func (s *Server) batchGet(w http.ResponseWriter, r *http.Request, docID string) {
+	out := struct {
+		SpreadsheetID string       `json:"spreadsheetId"`
+		ValueRanges   []valueRange `json:"valueRanges"`
+	}{SpreadsheetID: docID}
+	for _, rng := range r.URL.Query()["ranges"] {
+		vr, code, msg := s.read(docID, rng)
+		if code != http.StatusOK {
+			httpError(w, code, msg)
+			return
+		}
+		out.ValueRanges = append(out.ValueRanges, vr)
+	}
+	writeJSON(w, out)
+}
+
+// decodeValues reads a ValueRange body. Values arrive as JSON scalars; they are stored
+// as the text a sheet would show for them.
+func decodeValues(r *http.Request) ([][]string, error) {
+	var body struct {
+		Values [][]interface{} `json:"values"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		return nil, err
+	}
+	rows := make([][]string, len(body.Values))
+	for i := range body.Values {
+		rows[i] = make([]string, len(body.Values[i]))
+		for j, v := range body.Values[i] {
+			if v != nil {
+				rows[i][j] = fmt.Sprint(v)
+			}
+		}
+	}
+	return rows, nil
+}
+
+func (s *Server) target(w http.ResponseWriter, docID, rng string) (*sheet, gsheet.Range, bool) {
+	a, err := gsheet.ParseRange(rng)
+	if err != nil {
+		httpError(w, http.StatusBadRequest, err.Error())
+		return nil, a, false
+	}
+	d, ok := s.docs[docID]
+	if !ok {
+		httpError(w, http.StatusNotFound, "Requested entity was not found.")
+		return nil, a, false
+	}
+	sh := d.find(a.Title)
+	if sh == nil {
+		httpError(w, http.StatusBadRequest, "Unable to parse range: "+rng)
+		return nil, a, false
+	}
+	return sh, a, true
+}
+
+func (s *Server) valuesUpdate(w http.ResponseWriter, r *http.Request, docID, rng string) {
+	sh, a, ok := s.target(w, docID, rng)
+	if !ok {
+		return
+	}
+	rows, err := decodeValues(r)
+	if err != nil {
+		httpError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	// Like Sheets, refuse data that does not fit a bounded range rather than writing
+	// past it.
+	if (a.Row1 >= 0 && len(rows) > a.Row1-a.Row0+1) || (a.Col1 >= 0 && maxWidth(rows) > a.Col1-a.Col0+1) {
+		httpError(w, http.StatusBadRequest, fmt.Sprintf("Requested writing within range [%s], but tried writing to row [%d]", rng, a.Row0+len(rows)))
+		return
+	}
+	for i, row := range rows {
+		sh.set(a.Row0+i, a.Col0, row)
+	}
+	writeJSON(w, map[string]interface{}{"spreadsheetId": docID, "updatedRange": rng, "updatedRows": len(rows)})
+}
+
+func (s *Server) valuesAppend(w http.ResponseWriter, r *http.Request, docID, rng string) {
+	sh, a, ok := s.target(w, docID, rng)
+	if !ok {
+		return
+	}
+	rows, err := decodeValues(r)
+	if err != nil {
+		httpError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	// Appends go after the last non-empty row, which is where Sheets places them for
+	// a table that starts at the top of the sheet.
+	last := len(sh.rows)
+	for last > 0 && len(trimRow(sh.rows[last-1])) == 0 {
+		last--
+	}
+	for i, row := range rows {
+		sh.set(last+i, a.Col0, row)
+	}
+	writeJSON(w, map[string]interface{}{"spreadsheetId": docID, "updates": map[string]interface{}{"updatedRows": len(rows)}})
+}
+
+func (sh *sheet) set(row, col int, values []string) {
+	for len(sh.rows) <= row {
+		sh.rows = append(sh.rows, nil)
+	}
+	for len(sh.rows[row]) < col+len(values) {
+		sh.rows[row] = append(sh.rows[row], "")
+	}
+	copy(sh.rows[row][col:], values)
+}
+
+// export serves the CSV download aggregate reads: ?key=<doc>&exportFormat=csv&gid=<id>.
+// Rows are padded to the sheet's widest row, as a CSV export is rectangular.
+func (s *Server) export(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	if q.Get("exportFormat") != "csv" {
+		httpError(w, http.StatusBadRequest, "only exportFormat=csv is supported")
+		return
+	}
+	d, ok := s.docs[q.Get("key")]
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	gid, err := strconv.ParseInt(q.Get("gid"), 10, 64)
+	if err != nil {
+		httpError(w, http.StatusBadRequest, "bad gid")
+		return
+	}
+	sh := d.byID(gid)
+	if sh == nil {
+		http.NotFound(w, r)
+		return
+	}
+	width := maxWidth(sh.rows)
+	w.Header().Set("Content-Type", "text/csv")
+	cw := csv.NewWriter(w)
+	for _, row := range sh.rows {
+		padded := make([]string, width)
+		copy(padded, row)
+		_ = cw.Write(padded)
+	}
+	cw.Flush()
+}
+
+func maxWidth(rows [][]string) int {
+	n := 0
+	for _, row := range rows {
+		n = max(n, len(row))
+	}
+	return n
+}
+
+func trimRow(row []string) []string {
+	for len(row) > 0 && row[len(row)-1] == "" {
+		row = row[:len(row)-1]
+	}
+	return row
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// httpError answers in the error shape googleapi decodes, so the client surfaces the
+// message the way it would a real API error.
+func httpError(w http.ResponseWriter, code int, msg string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{
+		"error": map[string]interface{}{"code": code, "message": msg},
+	})
+}
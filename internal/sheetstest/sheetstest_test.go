@@ -0,0 +1,123 @@
+package sheetstest
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"google.golang.org/api/sheets/v4"
+
+	"github.com/fzerorubigd/bgg-hotness/internal/sheetsclient"
+)
+
+// client returns a real Sheets client pointed at s, so the stand-in is exercised through
+// the same request encoding the commands use.
+func client(t *testing.T, s *Server) *sheets.Service {
+	t.Helper()
+	srv, err := sheetsclient.New(context.Background(), sheetsclient.Config{Endpoint: s.URL + "/"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return srv
+}
+
+func TestSpreadsheetLifecycle(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+	s.AddSheet("doc", "Aggregate", [][]string{{"Date", "1", "2"}})
+	srv := client(t, s)
+	ctx := context.Background()
+
+	// addWorksheet + updateData, the first two commands of a daily run.
+	if _, err := srv.Spreadsheets.BatchUpdate("doc", &sheets.BatchUpdateSpreadsheetRequest{
+		Requests: []*sheets.Request{{AddSheet: &sheets.AddSheetRequest{Properties: &sheets.SheetProperties{Title: "2026-08-01"}}}},
+	}).Context(ctx).Do(); err != nil {
+		t.Fatalf("addSheet: %v", err)
+	}
+	data := [][]interface{}{{"Rank", "BGGID"}, {"1", "174430"}}
+	if _, err := srv.Spreadsheets.Values.Update("doc", "'2026-08-01'!A1:B2", &sheets.ValueRange{Values: data}).
+		ValueInputOption("RAW").Context(ctx).Do(); err != nil {
+		t.Fatalf("values.update: %v", err)
+	}
+	// appendData onto Aggregate.
+	if _, err := srv.Spreadsheets.Values.Append("doc", "Aggregate", &sheets.ValueRange{Values: [][]interface{}{{"2026-08-01", "174430", 266192}}}).
+		ValueInputOption("RAW").Context(ctx).Do(); err != nil {
+		t.Fatalf("values.append: %v", err)
+	}
+
+	vr, err := srv.Spreadsheets.Values.Get("doc", "'2026-08-01'!A1:E").Context(ctx).Do()
+	if err != nil {
+		t.Fatalf("values.get: %v", err)
+	}
+	if got := fmt.Sprint(vr.Values); got != "[[Rank BGGID] [1 174430]]" {
+		t.Errorf("values.get = %s", got)
+	}
+	want := [][]string{{"Date", "1", "2"}, {"2026-08-01", "174430", "266192"}}
+	if got := s.Rows("doc", "Aggregate"); !reflect.DeepEqual(got, want) {
+		t.Errorf("Aggregate after append = %v, want %v", got, want)
+	}
+
+	sp, err := srv.Spreadsheets.Get("doc").Context(ctx).Do()
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if len(sp.Sheets) != 2 || sp.Sheets[1].Properties.Title != "2026-08-01" {
+		t.Fatalf("sheets = %+v", sp.Sheets)
+	}
+
+	// removeWorksheet.
+	if _, err := srv.Spreadsheets.BatchUpdate("doc", &sheets.BatchUpdateSpreadsheetRequest{
+		Requests: []*sheets.Request{{DeleteSheet: &sheets.DeleteSheetRequest{SheetId: sp.Sheets[1].Properties.SheetId}}},
+	}).Context(ctx).Do(); err != nil {
+		t.Fatalf("deleteSheet: %v", err)
+	}
+	if got := s.Titles("doc"); !reflect.DeepEqual(got, []string{"Aggregate"}) {
+		t.Errorf("titles after delete = %v", got)
+	}
+}
+
+// Sheets refuses a duplicate title and writes past a bounded range; the stand-in must
+// too, or a malformed command list would pass here and fail in production.
+func TestRejectsWhatSheetsRejects(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+	s.AddSheet("doc", "2026-08-01", nil)
+	srv := client(t, s)
+	ctx := context.Background()
+
+	if _, err := srv.Spreadsheets.BatchUpdate("doc", &sheets.BatchUpdateSpreadsheetRequest{
+		Requests: []*sheets.Request{{AddSheet: &sheets.AddSheetRequest{Properties: &sheets.SheetProperties{Title: "2026-08-01"}}}},
+	}).Context(ctx).Do(); err == nil {
+		t.Error("adding a duplicate title should fail")
+	}
+	if _, err := srv.Spreadsheets.Values.Update("doc", "2026-08-01!A1:E1", &sheets.ValueRange{Values: [][]interface{}{{"a"}, {"b"}}}).
+		ValueInputOption("RAW").Context(ctx).Do(); err == nil {
+		t.Error("writing two rows into a one-row range should fail")
+	}
+	if _, err := srv.Spreadsheets.Values.Get("doc", "missing!A1").Context(ctx).Do(); err == nil {
+		t.Error("reading a missing worksheet should fail")
+	}
+}
+
+func TestExportCSV(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+	gid := s.AddSheet("doc", "Aggregate", [][]string{{"Date", "1", "2"}, {"2026-08-01", "174430"}})
+
+	resp, err := http.Get(fmt.Sprintf("%s/feeds/download/spreadsheets/Export?key=doc&exportFormat=csv&gid=%d", s.URL, gid))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	rows, err := csv.NewReader(resp.Body).ReadAll()
+	if err != nil {
+		t.Fatalf("export is not CSV: %v", err)
+	}
+	want := [][]string{{"Date", "1", "2"}, {"2026-08-01", "174430", ""}}
+	if !reflect.DeepEqual(rows, want) {
+		t.Errorf("export = %v, want a rectangular %v", rows, want)
+	}
+}
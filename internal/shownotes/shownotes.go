@@ -0,0 +1,282 @@
+// Package shownotes is the shownotes subcommand: the hotness segment of a podcast
+// episode's notes. It ranks a period from the snapshot store, compares it with the
+// period before (by default the same number of days just before it) and renders the
+// result through a Go text/template: the top list, the new entries, the biggest
+// movers and the games that dropped out, each linked to its BGG page.
+//
+// The built-in templates are Markdown in English and Farsi; -template renders a
+// user's own file instead, Markdown, HTML or anything text, with the same data (Notes)
+// and functions:
+//
+//	num     an integer in the language's digits (Persian digits for fa)
+//	date    a date as YYYY-MM-DD in the language's digits
+//	signed  an integer with its sign, +3 or -2, in the language's digits
+//	abs     the absolute value of an integer
+//
+// The Farsi template wraps the notes in dir="rtl" and each game name in <bdi>, so a
+// Latin name inside Persian text keeps its own direction.
+package shownotes
+
+import (
+	"bytes"
+	"context"
+	"embed"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/fzerorubigd/bgg-hotness/internal/aggregate"
+	"github.com/fzerorubigd/bgg-hotness/internal/config"
+	"github.com/fzerorubigd/bgg-hotness/internal/history"
+	"github.com/fzerorubigd/bgg-hotness/internal/snapshot"
+)
+
+//go:embed templates
+var templates embed.FS
+
+// Langs lists the languages with a built-in template, for flag help.
+const Langs = "en, fa"
+
+// Game is a game in the notes.
+type Game struct {
+	Rank int
+	// PreviousRank is its rank in the previous period, 0 when it was not ranked.
+	PreviousRank int
+	// Change is the places it climbed, negative for a fall, 0 when it is new.
+	Change int
+	// New is set when it was not in the previous period's top list.
+	New  bool
+	ID   int64
+	Name string
+	URL  string
+}
+
+// Notes is the data a template renders.
+type Notes struct {
+	Lang                               string
+	From, To, PreviousFrom, PreviousTo time.Time
+	// Days and PreviousDays are the recorded days in each period.
+	Days, PreviousDays int
+	Method             string
+	// Top is the period's top list; New the games on it that were not on the previous
+	// one; Climbers and Fallers the games on both that moved the most, biggest first.
+	Top, New, Climbers, Fallers []Game
+	// Dropped is the games on the previous top list that are not on this one, with
+	// their previous rank and Rank 0.
+	Dropped []Game
+}
+
+// Period is what the notes cover.
+type Period struct {
+	From, To, PreviousFrom, PreviousTo time.Time
+	Method                             string
+	// Top is the length of the top list, Movers of the climbers and fallers lists.
+	Top, Movers int
+}
+
+// Compose ranks and compares the periods of p from h.
+func Compose(h history.History, p Period) (Notes, error) {
+	n := Notes{From: p.From, To: p.To, PreviousFrom: p.PreviousFrom, PreviousTo: p.PreviousTo, Method: p.Method}
+	if n.Method == "" {
+		n.Method = "schulze"
+	}
+	days, err := h.Range(p.From, p.To)
+	if err != nil {
+		return Notes{}, err
+	}
+	if len(days) == 0 {
+		return Notes{}, fmt.Errorf("%w between %s and %s", history.ErrNoDay, p.From.Format(time.DateOnly), p.To.Format(time.DateOnly))
+	}
+	prevDays, err := h.Range(p.PreviousFrom, p.PreviousTo)
+	if err != nil {
+		return Notes{}, err
+	}
+	n.Days, n.PreviousDays = len(days), len(prevDays)
+
+	cur, err := aggregate.RankDays(n.Method, days)
+	if err != nil {
+		return Notes{}, err
+	}
+	prev, err := aggregate.RankDays(n.Method, prevDays)
+	if err != nil {
+		return Notes{}, err
+	}
+	cmp := aggregate.Compare(cur, prev, p.Top)
+
+	for _, m := range cmp.Games {
+		g := Game{Rank: m.Rank, PreviousRank: m.PreviousRank, Change: m.Change, New: m.New, ID: m.ID, Name: name(m.Placed), URL: bggURL(m.ID)}
+		n.Top = append(n.Top, g)
+		switch {
+		case g.New:
+			n.New = append(n.New, g)
+		case g.Change > 0:
+			n.Climbers = append(n.Climbers, g)
+		case g.Change < 0:
+			n.Fallers = append(n.Fallers, g)
+		}
+	}
+	sort.SliceStable(n.Climbers, func(i, j int) bool { return n.Climbers[i].Change > n.Climbers[j].Change })
+	sort.SliceStable(n.Fallers, func(i, j int) bool { return n.Fallers[i].Change < n.Fallers[j].Change })
+	movers := max(p.Movers, 0)
+	n.Climbers = n.Climbers[:min(movers, len(n.Climbers))]
+	n.Fallers = n.Fallers[:min(movers, len(n.Fallers))]
+	for _, d := range cmp.Dropped {
+		n.Dropped = append(n.Dropped, Game{PreviousRank: d.Rank, ID: d.ID, Name: name(d), URL: bggURL(d.ID)})
+	}
+	return n, nil
+}
+
+func name(p aggregate.Placed) string {
+	if p.Name != "" {
+		return p.Name
+	}
+	return fmt.Sprintf("BGG #%d", p.ID)
+}
+
+func bggURL(id int64) string {
+	return fmt.Sprintf("https://boardgamegeek.com/boardgame/%d/", id)
+}
+
+// Template returns the template for lang: the built-in one, or file when it is set.
+func Template(lang, file string) (*template.Template, error) {
+	t := template.New("notes").Funcs(funcs(lang))
+	if file != "" {
+		b, err := os.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		return t.Parse(string(b))
+	}
+	b, err := templates.ReadFile("templates/" + lang + ".md.tmpl")
+	if err != nil {
+		return nil, fmt.Errorf("no built-in template for %q, want one of %s", lang, Langs)
+	}
+	return t.Parse(string(b))
+}
+
+var persianDigits = strings.NewReplacer(
+	"0", "۰", "1", "۱", "2", "۲", "3", "۳", "4", "۴",
+	"5", "۵", "6", "۶", "7", "۷", "8", "۸", "9", "۹",
+)
+
+func funcs(lang string) template.FuncMap {
+	digits := func(s string) string { return s }
+	if lang == "fa" {
+		digits = persianDigits.Replace
+	}
+	return template.FuncMap{
+		"num":  func(n int) string { return digits(strconv.Itoa(n)) },
+		"date": func(t time.Time) string { return digits(t.Format(time.DateOnly)) },
+		"signed": func(n int) string {
+			if n > 0 {
+				return "+" + digits(strconv.Itoa(n))
+			}
+			return digits(strconv.Itoa(n))
+		},
+		"abs": func(n int) int {
+			if n < 0 {
+				return -n
+			}
+			return n
+		},
+	}
+}
+
+// Render executes t with n into w.
+func Render(w io.Writer, t *template.Template, n Notes) error {
+	return t.Execute(w, n)
+}
+
+// Run renders the notes for a period to stdout or -out.
+func Run(ctx context.Context, cfg config.Config, args []string) error {
+	fs := flag.NewFlagSet("shownotes", flag.ContinueOnError)
+	var (
+		archiveDir, lang, tmplFile, out string
+		from, to, prevFrom, prevTo      string
+		p                               Period
+	)
+	fs.StringVar(&archiveDir, "archive-dir", cfg.ArchiveDir, "Directory of daily lists to take the rankings from, as fetch -archive-dir and cleanup write it")
+	fs.StringVar(&from, "from", "", "First day of the period, YYYY-MM-DD, e.g. the day after the last episode (default 14 days before -to)")
+	fs.StringVar(&to, "to", "", "Last day of the period, YYYY-MM-DD (default the latest recorded day)")
+	fs.StringVar(&prevFrom, "previous-from", "", "First day of the period to compare with (default as many days before -previous-to as the period has)")
+	fs.StringVar(&prevTo, "previous-to", "", "Last day of the period to compare with (default the day before -from)")
+	fs.StringVar(&p.Method, "method", "schulze", "Ranking method, one of "+aggregate.Methods)
+	fs.IntVar(&p.Top, "top", 10, "Number of games on the top list")
+	fs.IntVar(&p.Movers, "movers", 5, "Number of games on the climbers and fallers lists")
+	fs.StringVar(&lang, "lang", "en", "Language of the notes, one of "+Langs+"; with -template it only picks the digits")
+	fs.StringVar(&tmplFile, "template", "", "Go text/template file to render instead of the built-in one")
+	fs.StringVar(&out, "out", "", "File to write the notes to instead of stdout")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if archiveDir == "" {
+		return errors.New("-archive-dir (or archive_dir in the config) is required")
+	}
+	if p.Top < 0 || p.Movers < 0 {
+		return errors.New("-top and -movers cannot be negative")
+	}
+	h := history.History{Store: snapshot.Store{Dir: archiveDir}}
+
+	var err error
+	for _, d := range []struct {
+		flag string
+		v    string
+		t    *time.Time
+	}{{"from", from, &p.From}, {"to", to, &p.To}, {"previous-from", prevFrom, &p.PreviousFrom}, {"previous-to", prevTo, &p.PreviousTo}} {
+		if d.v == "" {
+			continue
+		}
+		if *d.t, err = time.Parse(time.DateOnly, d.v); err != nil {
+			return fmt.Errorf("-%s %q is not YYYY-MM-DD", d.flag, d.v)
+		}
+	}
+	if p.To.IsZero() {
+		if p.To, err = h.Latest(); err != nil {
+			return err
+		}
+	}
+	if p.From.IsZero() {
+		p.From = p.To.AddDate(0, 0, -13)
+	}
+	if p.PreviousTo.IsZero() {
+		_, p.PreviousTo = aggregate.PreviousPeriod(p.From, p.To)
+	}
+	if p.PreviousFrom.IsZero() {
+		p.PreviousFrom = p.PreviousTo.AddDate(0, 0, -int(p.To.Sub(p.From).Hours()/24))
+	}
+	if p.To.Before(p.From) || p.PreviousTo.Before(p.PreviousFrom) {
+		return errors.New("a period ends before it starts")
+	}
+
+	t, err := Template(lang, tmplFile)
+	if err != nil {
+		return err
+	}
+	n, err := Compose(h, p)
+	if err != nil {
+		return err
+	}
+	n.Lang = lang
+	var b bytes.Buffer
+	if err := Render(&b, t, n); err != nil {
+		return err
+	}
+	if out == "" {
+		_, err := os.Stdout.Write(b.Bytes())
+		return err
+	}
+	if dir := filepath.Dir(out); dir != "." {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return err
+		}
+	}
+	return os.WriteFile(out, b.Bytes(), 0o644)
+}
@@ -0,0 +1,145 @@
+package shownotes
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/fzerorubigd/bgg-hotness/internal/config"
+	"github.com/fzerorubigd/bgg-hotness/internal/history"
+	"github.com/fzerorubigd/bgg-hotness/internal/snapshot"
+)
+
+func day(d int) time.Time { return time.Date(2026, 8, d, 0, 0, 0, 0, time.UTC) }
+
+func testNotes(t *testing.T) Notes {
+	t.Helper()
+	s := snapshot.Store{Dir: t.TempDir()}
+	header := []string{"Rank", "BGGID", "Change", "Link", "Name"}
+	for title, rows := range map[string][][]string{
+		"2026-08-01": {header, {"1", "1", "", "", "Alpha"}, {"2", "4", "", "", "Delta"}, {"3", "2", "", "", "Beta"}, {"4", "5", "", "", "Epsilon"}},
+		"2026-08-02": {header, {"1", "1", "", "", "Alpha"}, {"2", "4", "", "", "Delta"}, {"3", "2", "", "", "Beta"}, {"4", "5", "", "", "Epsilon"}},
+		"2026-08-03": {header, {"1", "3", "", "", "Gamma & Sons"}, {"2", "5", "", "", "Epsilon"}, {"3", "2", "", "", "Beta"}, {"4", "1", "", "", "Alpha"}},
+		"2026-08-04": {header, {"1", "3", "", "", "Gamma & Sons"}, {"2", "5", "", "", "Epsilon"}, {"3", "2", "", "", "Beta"}, {"4", "1", "", "", "Alpha"}},
+	} {
+		if err := s.Write(title, rows); err != nil {
+			t.Fatal(err)
+		}
+	}
+	n, err := Compose(history.History{Store: s}, Period{
+		From: day(3), To: day(4), PreviousFrom: day(1), PreviousTo: day(2), Top: 3, Movers: 5,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return n
+}
+
+func TestCompose(t *testing.T) {
+	n := testNotes(t)
+	ids := func(gs []Game) (out []int64) {
+		for _, g := range gs {
+			out = append(out, g.ID)
+		}
+		return out
+	}
+	check := func(name string, got []Game, want ...int64) {
+		t.Helper()
+		if g := ids(got); !slices.Equal(g, want) {
+			t.Errorf("%s = %v, want %v", name, g, want)
+		}
+	}
+	check("Top", n.Top, 3, 5, 2)
+	// Epsilon was 4th, below the previous top 3, so it is new to the list.
+	check("New", n.New, 3, 5)
+	check("Climbers", n.Climbers)
+	check("Fallers", n.Fallers)
+	check("Dropped", n.Dropped, 1, 4)
+	if n.Top[2].PreviousRank != 3 || n.Top[2].Change != 0 || n.Days != 2 || n.Method != "schulze" {
+		t.Errorf("notes = %+v", n)
+	}
+	if n.Top[0].URL != "https://boardgamegeek.com/boardgame/3/" {
+		t.Errorf("URL = %q", n.Top[0].URL)
+	}
+}
+
+func render(t *testing.T, lang, file string, n Notes) string {
+	t.Helper()
+	tmpl, err := Template(lang, file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var b strings.Builder
+	if err := Render(&b, tmpl, n); err != nil {
+		t.Fatal(err)
+	}
+	return b.String()
+}
+
+func TestRenderEnglish(t *testing.T) {
+	got := render(t, "en", "", testNotes(t))
+	for _, s := range []string{
+		"## BGG hotness, 2026-08-03 to 2026-08-04\n",
+		"1. [Gamma & Sons](https://boardgamegeek.com/boardgame/3/) (new)\n",
+		"3. [Beta](https://boardgamegeek.com/boardgame/2/)\n",
+		"### Dropped out\n\n- [Alpha](https://boardgamegeek.com/boardgame/1/), 1 last time\n",
+	} {
+		if !strings.Contains(got, s) {
+			t.Errorf("English notes do not contain %q:\n%s", s, got)
+		}
+	}
+	if strings.Contains(got, "Biggest") {
+		t.Errorf("empty mover sections rendered:\n%s", got)
+	}
+}
+
+var links = regexp.MustCompile(`\(https://[^)]*\)`)
+
+func TestRenderFarsi(t *testing.T) {
+	got := render(t, "fa", "", testNotes(t))
+	for _, s := range []string{
+		`<div dir="rtl" lang="fa">`,
+		"از ۲۰۲۶-۰۸-۰۳ تا ۲۰۲۶-۰۸-۰۴",
+		"### ۳ بازی برتر",
+		"- **۱.** [<bdi>Gamma &amp; Sons</bdi>](https://boardgamegeek.com/boardgame/3/) (تازه‌وارد)",
+		"رتبهٔ پیشین ۲",
+	} {
+		if !strings.Contains(got, s) {
+			t.Errorf("Farsi notes do not contain %q:\n%s", s, got)
+		}
+	}
+	if strings.ContainsAny(links.ReplaceAllString(got, ""), "0123456789") {
+		t.Errorf("Farsi notes have Latin digits outside links:\n%s", got)
+	}
+}
+
+func TestUserTemplate(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "notes.html")
+	tmpl := `<ol>{{range .Top}}<li value="{{.Rank}}">{{.Name}} {{signed .Change}}</li>{{end}}</ol> {{num .Days}}`
+	if err := os.WriteFile(file, []byte(tmpl), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	n := testNotes(t)
+	n.Top[2].Change = -2
+	if got, want := render(t, "fa", file, n), `<ol><li value="1">Gamma & Sons ۰</li><li value="2">Epsilon +۲</li><li value="3">Beta -۲</li></ol> ۲`; got != want {
+		t.Errorf("user template = %q, want %q", got, want)
+	}
+	if _, err := Template("de", ""); err == nil {
+		t.Error("Template(de) succeeded without a built-in German template")
+	}
+}
+
+// A negative list length is refused before it reaches the slicing.
+func TestRunRejectsNegativeCounts(t *testing.T) {
+	for _, arg := range []string{"-top=-1", "-movers=-1"} {
+		err := Run(context.Background(), config.Config{}, []string{"-archive-dir", t.TempDir(), arg})
+		if err == nil || !strings.Contains(err.Error(), "negative") {
+			t.Errorf("Run %s = %v, want a negative count error", arg, err)
+		}
+	}
+}
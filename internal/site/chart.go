@@ -0,0 +1,80 @@
+package site
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/fzerorubigd/bgg-hotness/internal/history"
+)
+
+// chart is a rank-over-time line chart laid out for the game template: rank 1 at the
+// top, one x step per recorded day. Days off the list break the line, so a game that
+// drops out and comes back is drawn as separate runs; a run of one day is a dot.
+type chart struct {
+	Width, Height            int
+	Left, Right, Top, Bottom int
+	MaxRank                  int
+	FirstDate, LastDate      string
+	// Lines are polyline point lists, "x,y x,y ...".
+	Lines []string
+	Dots  []dot
+}
+
+type dot struct {
+	X, Y string
+}
+
+// minMaxRank keeps the y axis at the length of BGG's list, so the charts of different
+// games are on the same scale, unless a stored list is longer.
+const minMaxRank = 50
+
+func newChart(points []history.Point) chart {
+	c := chart{Width: 640, Height: 240, Left: 32, Right: 632, Top: 8, Bottom: 220, MaxRank: minMaxRank}
+	if len(points) == 0 {
+		return c
+	}
+	c.FirstDate = points[0].Date.Format(time.DateOnly)
+	c.LastDate = points[len(points)-1].Date.Format(time.DateOnly)
+	for _, p := range points {
+		c.MaxRank = max(c.MaxRank, p.Rank)
+	}
+
+	x := func(i int) string {
+		if len(points) == 1 {
+			return coord(float64(c.Left+c.Right) / 2)
+		}
+		return coord(float64(c.Left) + float64(i)*float64(c.Right-c.Left)/float64(len(points)-1))
+	}
+	y := func(rank int) string {
+		return coord(float64(c.Top) + float64(rank-1)*float64(c.Bottom-c.Top)/float64(c.MaxRank-1))
+	}
+
+	var run []string
+	flush := func(i int) {
+		switch len(run) {
+		case 0:
+		case 1:
+			c.Dots = append(c.Dots, dot{X: x(i - 1), Y: y(points[i-1].Rank)})
+		default:
+			c.Lines = append(c.Lines, strings.Join(run, " "))
+		}
+		run = nil
+	}
+	for i, p := range points {
+		if p.Rank == 0 {
+			flush(i)
+			continue
+		}
+		run = append(run, x(i)+","+y(p.Rank))
+	}
+	flush(len(points))
+	return c
+}
+
+// coord formats a coordinate with at most one decimal, which is finer than a pixel
+// and keeps the pages stable across platforms.
+func coord(v float64) string {
+	s := fmt.Sprintf("%.1f", v)
+	return strings.TrimSuffix(s, ".0")
+}
@@ -0,0 +1,282 @@
+// Package site is the site subcommand: it renders the daily lists in the snapshot store
+// as a static HTML site, so listeners get a browsable archive rather than a spreadsheet
+// link. The site is
+//
+//	index.html           the rolling ranking of the last -days days, and the archive
+//	months/YYYY-MM.html  the ranking of each month with a recorded day
+//	years/YYYY.html      the ranking of each year
+//	games/ID.html        a game's stats and an inline SVG chart of its rank over time
+//
+// Pages are plain HTML with inline CSS and SVG, no JavaScript, and depend only on the
+// store: a page's "updated" date is the last recorded day it covers, not the time of
+// the run, and a game's page stops at the last day the game was on the list. A rebuild
+// over the same store rewrites nothing, and a new day rewrites only the pages it adds
+// to, so the feed branch the site is committed to only changes when the data does.
+package site
+
+import (
+	"bytes"
+	"context"
+	"embed"
+	"errors"
+	"flag"
+	"fmt"
+	"html/template"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"github.com/fzerorubigd/bgg-hotness/internal/aggregate"
+	"github.com/fzerorubigd/bgg-hotness/internal/config"
+	"github.com/fzerorubigd/bgg-hotness/internal/history"
+	"github.com/fzerorubigd/bgg-hotness/internal/snapshot"
+)
+
+//go:embed templates
+var templates embed.FS
+
+var (
+	rankingPage = template.Must(template.ParseFS(templates, "templates/layout.html", "templates/ranking.html"))
+	gamePage    = template.Must(template.ParseFS(templates, "templates/layout.html", "templates/game.html"))
+)
+
+// Options is what a site is built with besides the store.
+type Options struct {
+	// Title is the site name shown on every page.
+	Title string
+	// Days is the window of the rolling ranking on the index.
+	Days int
+	// Method is the ranking method, one of aggregate.Methods.
+	Method string
+	// Count is the number of games on each ranking, 0 for all.
+	Count int
+}
+
+// Run builds the site from the archive into a directory.
+func Run(ctx context.Context, cfg config.Config, args []string) error {
+	fs := flag.NewFlagSet("site", flag.ContinueOnError)
+	var (
+		archiveDir, out string
+		opts            Options
+	)
+	fs.StringVar(&archiveDir, "archive-dir", cfg.ArchiveDir, "Directory of daily lists to build the site from, as fetch -archive-dir and cleanup write it")
+	fs.StringVar(&out, "out", "site", "Directory to write the site to")
+	fs.StringVar(&opts.Title, "title", "BGG Hotness", "Site name shown on every page")
+	fs.IntVar(&opts.Days, "days", 14, "Number of days the rolling ranking on the index covers")
+	fs.StringVar(&opts.Method, "method", "schulze", "Ranking method, one of "+aggregate.Methods)
+	fs.IntVar(&opts.Count, "count", 50, "Number of games on each ranking, 0 for all")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if archiveDir == "" {
+		return errors.New("-archive-dir (or archive_dir in the config) is required")
+	}
+	n, err := Build(history.History{Store: snapshot.Store{Dir: archiveDir}}, out, opts)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "site: %d page(s) changed in %s\n", n, out)
+	return nil
+}
+
+// Build renders the site for h into dir and returns how many pages it changed.
+func Build(h history.History, dir string, opts Options) (int, error) {
+	days, err := h.Range(time.Time{}, time.Time{})
+	if err != nil {
+		return 0, err
+	}
+	if len(days) == 0 {
+		return 0, history.ErrNoDay
+	}
+	b := builder{dir: dir, opts: opts, days: days, names: history.Names(days)}
+
+	// Months and years newest first, as the archive lists them.
+	for i := len(days) - 1; i >= 0; i-- {
+		d := days[i].Date
+		month := time.Date(d.Year(), d.Month(), 1, 0, 0, 0, 0, time.UTC)
+		if len(b.months) == 0 || !b.months[len(b.months)-1].from.Equal(month) {
+			b.months = append(b.months, link{
+				Label: month.Format("January 2006"),
+				Path:  "months/" + month.Format("2006-01") + ".html",
+				from:  month,
+				to:    month.AddDate(0, 1, -1),
+			})
+		}
+		year := time.Date(d.Year(), 1, 1, 0, 0, 0, 0, time.UTC)
+		if len(b.years) == 0 || !b.years[len(b.years)-1].from.Equal(year) {
+			b.years = append(b.years, link{
+				Label: year.Format("2006"),
+				Path:  "years/" + year.Format("2006") + ".html",
+				from:  year,
+				to:    year.AddDate(1, 0, -1),
+			})
+		}
+	}
+
+	last := days[len(days)-1].Date
+	index, err := b.ranking("", "Hot right now", last.AddDate(0, 0, -(max(opts.Days, 1)-1)), last)
+	if err != nil {
+		return 0, err
+	}
+	index.Months, index.Years = b.months, b.years
+	if err := b.write("index.html", rankingPage, index); err != nil {
+		return b.changed, err
+	}
+	for _, l := range slices.Concat(b.months, b.years) {
+		p, err := b.ranking("../", l.Label, l.from, l.to)
+		if err != nil {
+			return b.changed, err
+		}
+		if err := b.write(l.Path, rankingPage, p); err != nil {
+			return b.changed, err
+		}
+	}
+	for _, id := range history.IDs(days) {
+		if err := b.write(gamePath(id), gamePage, b.game(id)); err != nil {
+			return b.changed, err
+		}
+	}
+	return b.changed, nil
+}
+
+type builder struct {
+	dir     string
+	opts    Options
+	days    []history.Day
+	names   map[int64]string
+	months  []link
+	years   []link
+	changed int
+}
+
+// link is a month or year in the archive, and the days its page ranks.
+type link struct {
+	Label, Path string
+	from, to    time.Time
+}
+
+// page is what the layout template reads; the page types embed it.
+type page struct {
+	Site, Title, Updated string
+	// Root is the relative path back to the site root, "" or "../".
+	Root string
+}
+
+type rankedGame struct {
+	Rank  int
+	ID    int64
+	Name  string
+	Score int
+	Path  string
+}
+
+type rankingData struct {
+	page
+	From, To, Method string
+	Days             int
+	Games            []rankedGame
+	Months, Years    []link
+}
+
+func (b *builder) ranking(root, title string, from, to time.Time) (rankingData, error) {
+	var days []history.Day
+	for _, d := range b.days {
+		if !d.Date.Before(from) && !d.Date.After(to) {
+			days = append(days, d)
+		}
+	}
+	placed, err := aggregate.RankDays(b.opts.Method, days)
+	if err != nil {
+		return rankingData{}, err
+	}
+	if b.opts.Count > 0 && len(placed) > b.opts.Count {
+		placed = placed[:b.opts.Count]
+	}
+	method := b.opts.Method
+	if method == "" {
+		method = "schulze"
+	}
+	p := rankingData{
+		page:   b.page(root, title, days[len(days)-1].Date),
+		From:   from.Format(time.DateOnly),
+		To:     to.Format(time.DateOnly),
+		Method: method,
+		Days:   len(days),
+	}
+	// Names come from every day the site covers, not just the window's, so a game
+	// reads the same on each of its pages.
+	for _, g := range placed {
+		p.Games = append(p.Games, rankedGame{Rank: g.Rank, ID: g.ID, Name: b.name(g.ID), Score: g.Score, Path: gamePath(g.ID)})
+	}
+	return p, nil
+}
+
+type gameData struct {
+	page
+	ID          int64
+	Name        string
+	Stats       history.Stats
+	First, Last string
+	Chart       chart
+}
+
+func (b *builder) game(id int64) gameData {
+	points := history.Game(b.days, id)
+	// Cut at the last day on the list, so a game that dropped out keeps its page as of
+	// that day rather than re-rendering with every new day.
+	for len(points) > 1 && points[len(points)-1].Rank == 0 {
+		points = points[:len(points)-1]
+	}
+	st := history.Summarize(points)
+	return gameData{
+		page:  b.page("../", b.name(id), st.Last),
+		ID:    id,
+		Name:  b.name(id),
+		Stats: st,
+		First: st.First.Format(time.DateOnly),
+		Last:  st.Last.Format(time.DateOnly),
+		Chart: newChart(points),
+	}
+}
+
+// page is the layout data of a page whose content runs up to the day updated.
+func (b *builder) page(root, title string, updated time.Time) page {
+	return page{Site: b.opts.Title, Title: title, Updated: updated.Format(time.DateOnly), Root: root}
+}
+
+func (b *builder) name(id int64) string {
+	if n := b.names[id]; n != "" {
+		return n
+	}
+	return fmt.Sprintf("Game %d", id)
+}
+
+func gamePath(id int64) string {
+	return fmt.Sprintf("games/%d.html", id)
+}
+
+// write renders a page and replaces the file at path under the site directory, unless
+// it already holds exactly that, so an unchanged page keeps its file untouched.
+func (b *builder) write(path string, t *template.Template, data any) error {
+	var buf bytes.Buffer
+	if err := t.ExecuteTemplate(&buf, "layout", data); err != nil {
+		return fmt.Errorf("site: render %s: %w", path, err)
+	}
+	full := filepath.Join(b.dir, filepath.FromSlash(path))
+	if old, err := os.ReadFile(full); err == nil && bytes.Equal(old, buf.Bytes()) {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(full), 0o755); err != nil {
+		return err
+	}
+	tmp := full + ".tmp"
+	if err := os.WriteFile(tmp, buf.Bytes(), 0o644); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, full); err != nil {
+		return err
+	}
+	b.changed++
+	return nil
+}
@@ -0,0 +1,112 @@
+package site
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/fzerorubigd/bgg-hotness/internal/history"
+	"github.com/fzerorubigd/bgg-hotness/internal/snapshot"
+)
+
+func TestBuild(t *testing.T) {
+	s := snapshot.Store{Dir: t.TempDir()}
+	header := []string{"Rank", "BGGID", "Change", "Link", "Name"}
+	for title, rows := range map[string][][]string{
+		"2025-12-31": {header, {"1", "1", "", "", "Alpha"}},
+		"2026-01-01": {header, {"1", "2", "", "", "<Beta & Co>"}, {"2", "1", "", "", "Alpha"}},
+		"2026-01-02": {header, {"1", "2", "", "", "<Beta & Co>"}, {"2", "1", "", "", "Alpha"}},
+	} {
+		if err := s.Write(title, rows); err != nil {
+			t.Fatal(err)
+		}
+	}
+	h := history.History{Store: s}
+	dir := t.TempDir()
+	opts := Options{Title: "Test", Days: 14, Method: "schulze", Count: 50}
+
+	n, err := Build(h, dir, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		"games/1.html", "games/2.html", "index.html",
+		"months/2025-12.html", "months/2026-01.html", "years/2025.html", "years/2026.html",
+	}
+	var got []string
+	filepath.WalkDir(dir, func(path string, d os.DirEntry, err error) error {
+		if err == nil && !d.IsDir() {
+			rel, _ := filepath.Rel(dir, path)
+			got = append(got, filepath.ToSlash(rel))
+		}
+		return err
+	})
+	if !reflect.DeepEqual(got, want) || n != len(want) {
+		t.Errorf("Build wrote %d: %v, want %v", n, got, want)
+	}
+
+	read := func(name string) string {
+		b, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(b)
+	}
+	index := read("index.html")
+	for _, s := range []string{
+		`<a href="games/2.html">&lt;Beta &amp; Co&gt;</a>`,
+		`<a href="months/2026-01.html">January 2026</a>`,
+		`Updated 2026-01-02.`,
+	} {
+		if !strings.Contains(index, s) {
+			t.Errorf("index.html does not contain %q", s)
+		}
+	}
+	if month := read("months/2025-12.html"); !strings.Contains(month, `<a href="../games/1.html">Alpha</a>`) || strings.Contains(month, "Beta") {
+		t.Errorf("months/2025-12.html does not rank December alone:\n%s", month)
+	}
+	if game := read("games/1.html"); !strings.Contains(game, `<polyline class="line"`) || !strings.Contains(game, "3 of 3") {
+		t.Errorf("games/1.html has no chart or stats:\n%s", game)
+	}
+
+	// The same store builds the same site, and touches nothing.
+	if n, err := Build(h, dir, opts); err != nil || n != 0 {
+		t.Errorf("rebuild changed %d page(s), err %v; want 0", n, err)
+	}
+
+	// A new day without Alpha leaves Alpha's page as it was on its last day.
+	alpha := read("games/1.html")
+	if err := s.Write("2026-01-03", [][]string{header, {"1", "2", "", "", "<Beta & Co>"}}); err != nil {
+		t.Fatal(err)
+	}
+	if n, err := Build(h, dir, opts); err != nil || n != 4 {
+		t.Errorf("build after a new day changed %d page(s), err %v; want 4", n, err)
+	}
+	if got := read("games/1.html"); got != alpha || !strings.Contains(got, "Updated 2026-01-02.") {
+		t.Errorf("games/1.html changed with a day it is not on:\n%s", got)
+	}
+	if !strings.Contains(read("index.html"), "Updated 2026-01-03.") {
+		t.Error("index.html is not updated to the new day")
+	}
+}
+
+func TestChart(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2026, 1, d, 0, 0, 0, 0, time.UTC) }
+	var points []history.Point
+	for i, rank := range []int{1, 50, 0, 2, 0} {
+		points = append(points, history.Point{Date: day(i + 1), Rank: rank})
+	}
+	c := newChart(points)
+	if want := []string{"32,8 182,220"}; !reflect.DeepEqual(c.Lines, want) {
+		t.Errorf("Lines = %v, want %v", c.Lines, want)
+	}
+	if want := []dot{{"482", "12.3"}}; !reflect.DeepEqual(c.Dots, want) {
+		t.Errorf("Dots = %v, want %v", c.Dots, want)
+	}
+	if c.FirstDate != "2026-01-01" || c.LastDate != "2026-01-05" || c.MaxRank != 50 {
+		t.Errorf("chart = %+v", c)
+	}
+}
@@ -0,0 +1,124 @@
+// Package snapshot is the local store of worksheet snapshots: one CSV file per
+// worksheet title, holding the rows exactly as they were on the sheet. cleanup writes
+// a worksheet here before it asks for the worksheet to be removed, so the full daily
+// detail (rank, id, change, link, name) outlives the spreadsheet's retention window
+// instead of surviving only as the id-only row on the Aggregate sheet.
+package snapshot
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Store is a directory of snapshots. The zero value is not usable; Dir must be set.
+type Store struct {
+	Dir string
+}
+
+// Path returns the file a worksheet title is stored under. It fails for a title that
+// cannot be a single file name, rather than letting a title such as "../x" escape Dir.
+func (s Store) Path(title string) (string, error) {
+	if title == "" || title == "." || title == ".." || strings.ContainsAny(title, `/\`) {
+		return "", fmt.Errorf("snapshot: title %q is not a valid file name", title)
+	}
+	return filepath.Join(s.Dir, title+".csv"), nil
+}
+
+// Write stores rows under title, replacing any earlier snapshot of the same title. It
+// writes a temp file and renames it into place, so a crash mid-write cannot leave a
+// truncated snapshot that looks complete.
+func (s Store) Write(title string, rows [][]string) error {
+	path, err := s.Path(title)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(s.Dir, 0o755); err != nil {
+		return err
+	}
+
+	tmp := path + ".tmp"
+	f, err := os.Create(tmp)
+	if err != nil {
+		return err
+	}
+	w := csv.NewWriter(f)
+	// Rows read back from a sheet are ragged (trailing empty cells are omitted), which
+	// encoding/csv accepts on write and Read below accepts on read.
+	if err := w.WriteAll(rows); err != nil {
+		f.Close()
+		os.Remove(tmp)
+		return fmt.Errorf("snapshot: write %q: %w", title, err)
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// Read returns the rows stored under title. A missing snapshot is reported with an
+// error wrapping os.ErrNotExist.
+func (s Store) Read(title string) ([][]string, error) {
+	path, err := s.Path(title)
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	r := csv.NewReader(f)
+	r.FieldsPerRecord = -1
+	rows, err := r.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("snapshot: read %q: %w", title, err)
+	}
+	return rows, nil
+}
+
+// Exists reports whether a snapshot for title is already stored.
+func (s Store) Exists(title string) bool {
+	path, err := s.Path(title)
+	if err != nil {
+		return false
+	}
+	_, err = os.Stat(path)
+	return err == nil
+}
+
+// Titles returns the titles of every stored snapshot, sorted. A missing Dir is an empty
+// store, not an error.
+func (s Store) Titles() ([]string, error) {
+	entries, err := os.ReadDir(s.Dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var titles []string
+	for _, e := range entries {
+		if title, ok := strings.CutSuffix(e.Name(), ".csv"); ok && e.Type().IsRegular() {
+			titles = append(titles, title)
+		}
+	}
+	sort.Strings(titles)
+	return titles, nil
+}
+
+// Remove deletes the snapshot stored under title. A missing snapshot is reported with
+// an error wrapping os.ErrNotExist.
+func (s Store) Remove(title string) error {
+	path, err := s.Path(title)
+	if err != nil {
+		return err
+	}
+	return os.Remove(path)
+}
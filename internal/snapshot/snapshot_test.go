@@ -0,0 +1,100 @@
+package snapshot
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// A worksheet read back from Sheets is ragged (trailing blank cells are dropped), so the
+// round trip must preserve short rows rather than rejecting them on read.
+func TestWriteReadRoundTripRaggedRows(t *testing.T) {
+	s := Store{Dir: filepath.Join(t.TempDir(), "archive")} // not yet created: Write makes it
+	rows := [][]string{
+		{"Rank", "BGGID", "Change", "Link", "Name"},
+		{"1", "174430", "2", "https://boardgamegeek.com/boardgame/174430/", "Gloomhaven"},
+		{"2", "999999", "0", "https://boardgamegeek.com/boardgame/999999/"}, // blank name omitted upstream
+	}
+	if err := s.Write("2026-08-01", rows); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	got, err := s.Read("2026-08-01")
+	if err != nil {
+		t.Fatalf("Read: %v", err)
+	}
+	if !reflect.DeepEqual(got, rows) {
+		t.Errorf("round trip changed the rows:\n got %v\nwant %v", got, rows)
+	}
+	if !s.Exists("2026-08-01") {
+		t.Error("Exists should report a written snapshot")
+	}
+	if _, err := os.Stat(filepath.Join(s.Dir, "2026-08-01.csv.tmp")); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("the temp file must be renamed away, stat err = %v", err)
+	}
+}
+
+// A second Write of the same title replaces the snapshot rather than appending to it.
+func TestWriteReplaces(t *testing.T) {
+	s := Store{Dir: t.TempDir()}
+	if err := s.Write("2026-08-01", [][]string{{"a"}, {"b"}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Write("2026-08-01", [][]string{{"c"}}); err != nil {
+		t.Fatal(err)
+	}
+	got, err := s.Read("2026-08-01")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, [][]string{{"c"}}) {
+		t.Errorf("got %v, want the second write only", got)
+	}
+}
+
+// Titles come from the spreadsheet, so one that is not a plain file name must be refused
+// rather than written outside the store.
+func TestPathRejectsTitlesThatEscapeDir(t *testing.T) {
+	s := Store{Dir: t.TempDir()}
+	for _, title := range []string{"", ".", "..", "../x", "a/b", `a\b`} {
+		if err := s.Write(title, [][]string{{"x"}}); err == nil {
+			t.Errorf("Write(%q) should fail", title)
+		}
+	}
+}
+
+func TestReadMissingIsNotExist(t *testing.T) {
+	s := Store{Dir: t.TempDir()}
+	if _, err := s.Read("2026-08-01"); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("Read of a missing snapshot: err = %v, want os.ErrNotExist", err)
+	}
+	if s.Exists("2026-08-01") {
+		t.Error("Exists should be false for a missing snapshot")
+	}
+}
+
+func TestTitlesAndRemove(t *testing.T) {
+	s := Store{Dir: filepath.Join(t.TempDir(), "archive")}
+	if titles, err := s.Titles(); err != nil || len(titles) != 0 {
+		t.Fatalf("Titles of a missing dir = %v, %v; want empty, nil", titles, err)
+	}
+	for _, title := range []string{"2026-08-02", "Aggregate", "2026-08-01"} {
+		if err := s.Write(title, [][]string{{"x"}}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := s.Remove("Aggregate"); err != nil {
+		t.Fatal(err)
+	}
+	titles, err := s.Titles()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"2026-08-01", "2026-08-02"}; !reflect.DeepEqual(titles, want) {
+		t.Errorf("Titles = %v, want %v", titles, want)
+	}
+	if err := s.Remove("Aggregate"); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("Remove of a missing snapshot: err = %v, want os.ErrNotExist", err)
+	}
+}
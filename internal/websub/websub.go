@@ -0,0 +1,165 @@
+// Package websub is the websub subcommand: it tells a WebSub hub that feed URLs have new
+// content (a hub.mode=publish ping), so the hub pushes the new entries to subscribers
+// instead of them waiting for their next poll.
+//
+// The feeds name the hub as rel="hub" (aggregate -hub); aggregate -changed lists the
+// feed URLs a run actually changed, and the workflows run websub on that list after the
+// feed branch is pushed, because the hub fetches a topic as soon as it is told about it.
+// A failed ping is retried with a doubling delay; after the last attempt the error is
+// returned, but the other topics are still announced.
+package websub
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/fzerorubigd/bgg-hotness/internal/config"
+)
+
+// Publisher pings one hub. The zero values of the tuning fields pick the defaults.
+type Publisher struct {
+	Hub string
+	// Client sends the pings; nil means http.DefaultClient.
+	Client *http.Client
+	// Attempts is how often a ping is tried before giving up (default 3).
+	Attempts int
+	// Backoff is the wait before the second attempt, doubled before each one after it
+	// (default 2s).
+	Backoff time.Duration
+}
+
+// Publish announces each of topics to the hub and returns the errors of the ones that
+// failed every attempt.
+func (p Publisher) Publish(ctx context.Context, topics ...string) error {
+	var errs []error
+	for _, topic := range topics {
+		if err := p.publish(ctx, topic); err != nil {
+			errs = append(errs, fmt.Errorf("publish %s: %w", topic, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (p Publisher) publish(ctx context.Context, topic string) error {
+	attempts, wait := p.Attempts, p.Backoff
+	if attempts <= 0 {
+		attempts = 3
+	}
+	if wait <= 0 {
+		wait = 2 * time.Second
+	}
+	var err error
+	for i := 0; i < attempts; i++ {
+		if i > 0 {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(wait):
+			}
+			wait *= 2
+		}
+		var retry bool
+		if retry, err = p.ping(ctx, topic); err == nil || !retry {
+			return err
+		}
+	}
+	return err
+}
+
+// ping sends one publish request. It reports whether a failure is worth retrying: a
+// transport error, a 5xx or a 429 are; any other refusal is the hub rejecting the topic
+// and will not change.
+func (p Publisher) ping(ctx context.Context, topic string) (bool, error) {
+	form := url.Values{"hub.mode": {"publish"}, "hub.url": {topic}}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.Hub, strings.NewReader(form.Encode()))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	client := p.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return ctx.Err() == nil, err
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return false, nil
+	}
+	err = fmt.Errorf("hub answered %s: %s", resp.Status, strings.TrimSpace(string(body)))
+	return resp.StatusCode >= 500 || resp.StatusCode == http.StatusTooManyRequests, err
+}
+
+// Run announces the topics given as arguments and in -topics to the hub.
+func Run(ctx context.Context, cfg config.Config, args []string) error {
+	fs := flag.NewFlagSet("websub", flag.ContinueOnError)
+	var (
+		p          Publisher
+		topicsFile string
+	)
+	fs.StringVar(&p.Hub, "hub", cfg.Hub, "WebSub hub to announce the topics to")
+	fs.StringVar(&topicsFile, "topics", "", "File of topic URLs, one per line, as aggregate -changed writes it; a missing file announces nothing")
+	fs.IntVar(&p.Attempts, "attempts", 3, "Times to try each announcement before giving up")
+	fs.DurationVar(&p.Backoff, "backoff", 2*time.Second, "Wait before the second attempt, doubled for each one after it")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if p.Hub == "" {
+		return errors.New("-hub (or hub in the config, or WEBSUB_HUB) is required")
+	}
+	topics := fs.Args()
+	if topicsFile != "" {
+		fromFile, err := readTopics(topicsFile)
+		if err != nil {
+			return err
+		}
+		topics = append(topics, fromFile...)
+	}
+	return p.Publish(ctx, dedupe(topics)...)
+}
+
+// readTopics reads a topics file. A missing file is no topics: aggregate only creates
+// it when a feed changed.
+func readTopics(path string) ([]string, error) {
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	var topics []string
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		if t := strings.TrimSpace(sc.Text()); t != "" {
+			topics = append(topics, t)
+		}
+	}
+	return topics, sc.Err()
+}
+
+// dedupe drops repeated topics, keeping the first of each.
+func dedupe(topics []string) []string {
+	seen := make(map[string]bool, len(topics))
+	out := topics[:0:0]
+	for _, t := range topics {
+		if !seen[t] {
+			seen[t] = true
+			out = append(out, t)
+		}
+	}
+	return out
+}
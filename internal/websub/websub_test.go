@@ -0,0 +1,118 @@
+package websub
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/fzerorubigd/bgg-hotness/internal/config"
+)
+
+// hub is a stand-in WebSub hub: it records the topics it was told about and answers
+// with the next status in fail before accepting.
+type hub struct {
+	mu     sync.Mutex
+	fail   []int
+	topics []string
+	tries  int
+}
+
+func (h *hub) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.tries++
+	if r.Method != http.MethodPost || r.FormValue("hub.mode") != "publish" {
+		http.Error(w, "not a publish request", http.StatusBadRequest)
+		return
+	}
+	if len(h.fail) > 0 {
+		code := h.fail[0]
+		h.fail = h.fail[1:]
+		http.Error(w, "try later", code)
+		return
+	}
+	h.topics = append(h.topics, r.FormValue("hub.url"))
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func TestPublishRetriesTransientFailures(t *testing.T) {
+	h := &hub{fail: []int{http.StatusServiceUnavailable, http.StatusTooManyRequests}}
+	srv := httptest.NewServer(h)
+	defer srv.Close()
+
+	p := Publisher{Hub: srv.URL, Attempts: 3, Backoff: time.Millisecond}
+	if err := p.Publish(context.Background(), "https://example.org/feed.xml"); err != nil {
+		t.Fatal(err)
+	}
+	if h.tries != 3 || len(h.topics) != 1 || h.topics[0] != "https://example.org/feed.xml" {
+		t.Errorf("hub saw %d tries, topics %v", h.tries, h.topics)
+	}
+}
+
+// A refusal that is not transient is not retried, and does not keep the other topics
+// from being announced.
+func TestPublishGivesUpOnRefusal(t *testing.T) {
+	h := &hub{fail: []int{http.StatusBadRequest}}
+	srv := httptest.NewServer(h)
+	defer srv.Close()
+
+	p := Publisher{Hub: srv.URL, Attempts: 3, Backoff: time.Millisecond}
+	err := p.Publish(context.Background(), "https://example.org/a.xml", "https://example.org/b.xml")
+	if err == nil || !strings.Contains(err.Error(), "a.xml") {
+		t.Errorf("err = %v, want the refused topic", err)
+	}
+	if h.tries != 2 || len(h.topics) != 1 || h.topics[0] != "https://example.org/b.xml" {
+		t.Errorf("hub saw %d tries, topics %v", h.tries, h.topics)
+	}
+}
+
+func TestPublishRunsOutOfAttempts(t *testing.T) {
+	h := &hub{fail: []int{500, 502, 503, 504}}
+	srv := httptest.NewServer(h)
+	defer srv.Close()
+
+	p := Publisher{Hub: srv.URL, Attempts: 3, Backoff: time.Millisecond}
+	if err := p.Publish(context.Background(), "https://example.org/feed.xml"); err == nil {
+		t.Error("three failures should fail the publish")
+	}
+	if h.tries != 3 || len(h.topics) != 0 {
+		t.Errorf("hub saw %d tries, topics %v", h.tries, h.topics)
+	}
+}
+
+// Run announces the topics in the file aggregate -changed writes, once each, and a
+// missing file (no feed changed) announces nothing.
+func TestRunTopicsFile(t *testing.T) {
+	h := &hub{}
+	srv := httptest.NewServer(h)
+	defer srv.Close()
+
+	dir := t.TempDir()
+	if err := Run(context.Background(), config.Config{Hub: srv.URL}, []string{"-topics", filepath.Join(dir, "none")}); err != nil {
+		t.Fatal(err)
+	}
+	if h.tries != 0 {
+		t.Errorf("a missing topics file announced %v", h.topics)
+	}
+
+	file := filepath.Join(dir, "changed")
+	if err := os.WriteFile(file, []byte("https://example.org/feed.xml\nhttps://example.org/feed.json\n\nhttps://example.org/feed.xml\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := Run(context.Background(), config.Config{Hub: srv.URL}, []string{"-topics", file}); err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"https://example.org/feed.xml", "https://example.org/feed.json"}; strings.Join(h.topics, " ") != strings.Join(want, " ") {
+		t.Errorf("topics = %v, want %v", h.topics, want)
+	}
+
+	if err := Run(context.Background(), config.Config{}, []string{"https://example.org/feed.xml"}); err == nil {
+		t.Error("no hub should fail")
+	}
+}
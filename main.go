@@ -0,0 +1,147 @@
+// Command bgg-hotness records the BGG hot list every day, ranks the days of a window
+// into one list with the Schulze method and publishes the result to a Google
+// spreadsheet and Atom feeds.
+//
+//	bgg-hotness [global flags] <command> [flags]
+//
+// The global flags and the config file (-config, or BGG_HOTNESS_CONFIG) set what the
+// commands share; each command's own flags override them for that run. The hotness,
+// aggregate and cleanup directories build the same commands as standalone programs.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/fzerorubigd/bgg-hotness/internal/aggregate"
+	"github.com/fzerorubigd/bgg-hotness/internal/cleanup"
+	"github.com/fzerorubigd/bgg-hotness/internal/cli"
+	"github.com/fzerorubigd/bgg-hotness/internal/config"
+	"github.com/fzerorubigd/bgg-hotness/internal/hotness"
+	"github.com/fzerorubigd/bgg-hotness/internal/mcpserver"
+	"github.com/fzerorubigd/bgg-hotness/internal/serve"
+	"github.com/fzerorubigd/bgg-hotness/internal/sheetexec"
+	"github.com/fzerorubigd/bgg-hotness/internal/shownotes"
+	"github.com/fzerorubigd/bgg-hotness/internal/site"
+	"github.com/fzerorubigd/bgg-hotness/internal/websub"
+)
+
+type command struct {
+	name    string
+	aliases []string
+	summary string
+	run     cli.RunFunc
+}
+
+var commands = []command{
+	{"fetch", []string{"hotness"}, "record today's hot list on the sheet", hotness.Run},
+	{"aggregate", nil, "rank a window of days and publish it to the sheet and feeds", aggregate.Run},
+	{"stats", nil, "print how each game did on the hot list over a window", aggregate.RunStats},
+	{"cleanup", nil, "remove the worksheets the retention policy no longer keeps", cleanup.Run},
+	{"exec", []string{"sheetexec"}, "run a command list from stdin against a sheet or a directory", sheetexec.Run},
+	{"serve", nil, "serve the archived daily lists over HTTP/JSON", serve.Run},
+	{"site", nil, "render the archived daily lists as a static HTML site", site.Run},
+	{"shownotes", nil, "render a period's ranking and moves as podcast show notes", shownotes.Run},
+	{"mcp", nil, "serve MCP tools over the archived daily lists on stdio", mcpserver.Run},
+	{"feedcheck", nil, "check feed files against the feed invariants and Atom's rules", aggregate.RunCheck},
+	{"websub", nil, "tell a WebSub hub that feed URLs have new content", websub.Run},
+}
+
+func lookup(name string) (command, bool) {
+	for _, c := range commands {
+		if c.name == name {
+			return c, true
+		}
+		for _, a := range c.aliases {
+			if a == name {
+				return c, true
+			}
+		}
+	}
+	return command{}, false
+}
+
+// parse reads the global flags and the config file and returns the command to run
+// with its arguments. Usage goes to stderr.
+func parse(args []string, stderr io.Writer) (command, config.Config, []string, error) {
+	fs := flag.NewFlagSet("bgg-hotness", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	var (
+		configFile = fs.String("config", os.Getenv(config.EnvFile), "YAML config file shared by the commands")
+		flags      config.Config
+	)
+	fs.StringVar(&flags.DocumentID, "document-id", "", "The spreadsheet id (DOCUMENT_ID)")
+	fs.IntVar(&flags.PageID, "page-id", 0, "The gid of the Aggregate worksheet (PAGE_ID)")
+	fs.StringVar(&flags.Timezone, "timezone", "", "IANA timezone the date of a run is taken in (TZ)")
+	fs.StringVar(&flags.BGGEndpoint, "bgg-endpoint", "", "Base URL to send BGG API requests to instead of boardgamegeek.com (BGG_ENDPOINT)")
+	fs.StringVar(&flags.SheetsEndpoint, "sheets-endpoint", "", "Sheets API base URL (SHEETS_ENDPOINT)")
+	fs.StringVar(&flags.ExportEndpoint, "export-endpoint", "", "Base URL of the spreadsheet CSV export (SHEETS_EXPORT_ENDPOINT)")
+	fs.Usage = func() {
+		out := fs.Output()
+		fmt.Fprintf(out, "Usage: bgg-hotness [global flags] <command> [flags]\n\nCommands:\n")
+		for _, c := range commands {
+			name := c.name
+			if len(c.aliases) > 0 {
+				name += " (" + strings.Join(c.aliases, ", ") + ")"
+			}
+			fmt.Fprintf(out, "  %-22s %s\n", name, c.summary)
+		}
+		fmt.Fprintf(out, "\nGlobal flags:\n")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return command{}, config.Config{}, nil, err
+	}
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return command{}, config.Config{}, nil, flag.ErrHelp
+	}
+	cmd, ok := lookup(fs.Arg(0))
+	if !ok {
+		fs.Usage()
+		return command{}, config.Config{}, nil, fmt.Errorf("unknown command %q", fs.Arg(0))
+	}
+
+	cfg, err := config.Load(*configFile)
+	if err != nil {
+		return command{}, config.Config{}, nil, err
+	}
+	fs.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "document-id":
+			cfg.DocumentID = flags.DocumentID
+		case "page-id":
+			cfg.PageID = flags.PageID
+		case "timezone":
+			cfg.Timezone = flags.Timezone
+		case "bgg-endpoint":
+			cfg.BGGEndpoint = flags.BGGEndpoint
+		case "sheets-endpoint":
+			cfg.SheetsEndpoint = flags.SheetsEndpoint
+		case "export-endpoint":
+			cfg.ExportEndpoint = flags.ExportEndpoint
+		}
+	})
+	return cmd, cfg, fs.Args()[1:], nil
+}
+
+func main() {
+	cmd, cfg, args, err := parse(os.Args[1:], os.Stderr)
+	if err != nil {
+		if !errors.Is(err, flag.ErrHelp) {
+			fmt.Fprintf(os.Stderr, "bgg-hotness: %v\n", err)
+		}
+		os.Exit(2)
+	}
+	if err := cli.Setup(cfg); err != nil {
+		cli.Exit("bgg-hotness", err)
+	}
+	ctx, cnl := cli.Context()
+	err = cmd.run(ctx, cfg, args)
+	cnl()
+	cli.Exit(cmd.name, err)
+}
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"flag"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// A global flag overrides the config file, the file overrides nothing set, and the
+// command's own arguments are passed through untouched.
+func TestParse(t *testing.T) {
+	t.Setenv("DOCUMENT_ID", "")
+	t.Setenv("PAGE_ID", "")
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte("document_id: from-file\npage_id: 7\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	cmd, cfg, args, err := parse([]string{"-config", path, "-document-id", "from-flag", "hotness", "-bgg-endpoint", "http://x"}, io.Discard)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cmd.name != "fetch" {
+		t.Errorf("hotness resolved to %q, want the fetch command", cmd.name)
+	}
+	if cfg.DocumentID != "from-flag" || cfg.PageID != 7 {
+		t.Errorf("config = %+v, want the flag's document id and the file's page id", cfg)
+	}
+	if want := []string{"-bgg-endpoint", "http://x"}; !reflect.DeepEqual(args, want) {
+		t.Errorf("args = %v, want %v", args, want)
+	}
+}
+
+func TestParseUsageErrors(t *testing.T) {
+	if _, _, _, err := parse(nil, io.Discard); err != flag.ErrHelp {
+		t.Errorf("no command: err = %v, want flag.ErrHelp", err)
+	}
+	if _, _, _, err := parse([]string{"publish"}, io.Discard); err == nil {
+		t.Error("an unknown command should fail")
+	}
+}